@@ -1,15 +1,32 @@
 package main
 
-func appMain() {
+import (
+	"context"
+	"log"
+)
+
+// appMain runs MMA until ctx is cancelled, then drains every transport
+// and returns the process exit code.
+func appMain(ctx context.Context) int {
 	cfg := loadConfig()
 	memories := buildMemories(cfg)
-	ingestSvc := buildIngest(memories)
+	restoreMemories(cfg, memories)
+	ingestSvc := buildIngest(cfg, memories)
+
+	lc := newLifecycle(cfg.Shutdown.DrainTimeout())
 
-	startModbus(cfg, memories)
-	startMQTT(cfg, ingestSvc)
-	startREST(cfg, memories, ingestSvc)
-	startRawIngest(cfg, memories)
+	listeners := startModbus(ctx, lc, cfg, memories)
+	startSerial(ctx, lc, cfg, memories)
+	startMQTT(ctx, lc, cfg, ingestSvc)
+	pollers := startPollers(ctx, lc, cfg, memories)
+	startREST(ctx, lc, cfg, memories, ingestSvc, pollers, listeners)
+	startRawIngest(ctx, lc, cfg, memories)
+	startPersistence(ctx, lc, cfg, memories)
 
+	<-ctx.Done()
+	log.Println("Shutting down")
 
-	blockForever()
+	code := lc.wait()
+	snapshotOnShutdown(cfg, memories)
+	return code
 }
@@ -1,5 +0,0 @@
-package main
-
-func blockForever() {
-	select {}
-}
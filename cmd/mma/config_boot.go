@@ -16,5 +16,13 @@ func loadConfig() *config.AppConfig {
 		log.Fatal(err)
 	}
 
+	if err := cfg.ValidateSerial(); err != nil {
+		log.Fatal(err)
+	}
+
+	if err := cfg.ValidatePollers(); err != nil {
+		log.Fatal(err)
+	}
+
 	return cfg // ✅ NOT &cfg
 }
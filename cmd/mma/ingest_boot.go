@@ -2,10 +2,16 @@
 package main
 
 import (
+	"modbus-memory-appliance/internal/config"
 	"modbus-memory-appliance/internal/core"
 	"modbus-memory-appliance/internal/ingest"
 )
 
-func buildIngest(memories map[string]*core.Memory) *ingest.Service {
-	return ingest.New(memories)
+func buildIngest(cfg *config.AppConfig, memories map[string]*core.Memory) *ingest.Service {
+	svc := ingest.New(memories)
+
+	// Commands without a memory land on the default memory (opt-in)
+	svc.SetFallbackMemory(cfg.FallbackMemoryID())
+
+	return svc
 }
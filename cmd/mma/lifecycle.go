@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Process exit codes.
+const (
+	exitOK = 0
+
+	// exitShutdownIncomplete: the drain deadline expired and active
+	// connections were cut. (Startup and runtime failures exit with 1.)
+	exitShutdownIncomplete = 2
+)
+
+// lifecycle tracks running transports so shutdown can drain them.
+type lifecycle struct {
+	drain time.Duration
+
+	wg         sync.WaitGroup
+	incomplete atomic.Bool
+}
+
+func newLifecycle(drain time.Duration) *lifecycle {
+	return &lifecycle{drain: drain}
+}
+
+// run runs fn in a tracked goroutine. fn must return once the root
+// context is cancelled.
+func (lc *lifecycle) run(fn func()) {
+	lc.wg.Add(1)
+	go func() {
+		defer lc.wg.Done()
+		fn()
+	}()
+}
+
+// onShutdown calls stop with the drain deadline once ctx is cancelled.
+func (lc *lifecycle) onShutdown(ctx context.Context, name string, stop func(context.Context) error) {
+	lc.run(func() {
+		<-ctx.Done()
+
+		dctx, cancel := context.WithTimeout(context.Background(), lc.drain)
+		defer cancel()
+
+		if err := stop(dctx); err != nil {
+			log.Printf("%s: shutdown incomplete: %v", name, err)
+			lc.incomplete.Store(true)
+		}
+	})
+}
+
+// wait blocks until every transport has stopped and returns the exit
+// code. Transports that ignore the drain deadline are abandoned.
+func (lc *lifecycle) wait() int {
+	done := make(chan struct{})
+	go func() {
+		lc.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(lc.drain + time.Second):
+		log.Println("Shutdown: transports still running after drain deadline")
+		return exitShutdownIncomplete
+	}
+
+	if lc.incomplete.Load() {
+		return exitShutdownIncomplete
+	}
+
+	log.Println("Shutdown complete")
+	return exitOK
+}
@@ -1,6 +1,19 @@
 package main
 
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
 func main() {
-	handleCLI() 
-	appMain()
+	handleCLI()
+
+	// SIGINT (Ctrl+C) and SIGTERM (Docker, systemd) stop MMA gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := appMain(ctx)
+	stop()
+
+	os.Exit(code)
 }
@@ -1,61 +1,221 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net"
+	"time"
 
 	"modbus-memory-appliance/internal/config"
 	"modbus-memory-appliance/internal/core"
 	"modbus-memory-appliance/internal/modbus"
 )
 
-func startModbus(cfg *config.AppConfig, memories map[string]*core.Memory) {
+// policyResolver builds the resolver for one listener:
+// unitID + function code → memory, using the routing in effect on the
+// listener and filtered by its policy.
+func policyResolver(
+	cfg *config.AppConfig,
+	memories map[string]*core.Memory,
+	policy config.PortPolicy,
+) modbus.MemoryResolver {
+	routing := cfg.RoutingFor(policy)
+	fallback := routing.FallbackMemoryID(cfg.Memory)
 
-	// Resolver: port + unitID + function code → memory
-	resolver := func(port uint16, unitID uint8, fc uint8) *core.Memory {
-		memID, ok := cfg.Routing.UnitIDMap[unitID]
+	return func(unitID uint8, fc uint8) *core.Memory {
+		memID, ok := routing.Route(unitID, fallback)
 		if !ok {
 			return nil
 		}
 
-		policy, hasPolicy := cfg.Ports[port]
-		if hasPolicy {
-			if !policy.AllowsUnitID(unitID) ||
-				!policy.AllowsMemory(memID) ||
-				!policy.AllowsFunctionCode(fc) {
-				return nil
-			}
+		if !policy.AllowsUnitID(unitID) ||
+			!policy.AllowsMemory(memID) ||
+			!policy.AllowsFunctionCode(fc) {
+			return nil
 		}
 
 		return memories[memID]
 	}
+}
+
+// upstreamResolver builds the gateway routes of one listener, with one
+// connection pool per upstream entry, closed once ctx is cancelled. It
+// returns nil without upstreams.
+func upstreamResolver(
+	ctx context.Context,
+	lc *lifecycle,
+	cfg *config.AppConfig,
+	policy config.PortPolicy,
+) modbus.UpstreamResolver {
+	routing := cfg.RoutingFor(policy)
+	if len(routing.Upstreams) == 0 {
+		return nil
+	}
+
+	pools := make([]*modbus.Upstream, len(routing.Upstreams))
+	for i, u := range routing.Upstreams {
+		pools[i] = modbus.NewUpstream(u.Address, u.PoolSize(), u.Timeout())
+	}
+
+	// Don't leave upstream connections open past shutdown
+	lc.run(func() {
+		<-ctx.Done()
+		for _, up := range pools {
+			up.Close()
+		}
+	})
+
+	return func(unitID uint8, fc uint8) (*modbus.Upstream, uint8) {
+		i, ok := routing.Upstreams.Lookup(unitID)
+		if !ok {
+			return nil, 0
+		}
+
+		if !policy.AllowsUnitID(unitID) || !policy.AllowsFunctionCode(fc) {
+			return nil, 0
+		}
 
-	if len(cfg.Ports) == 0 {
-		return
+		return pools[i], routing.Upstreams[i].RemoteUnitID(unitID)
 	}
+}
+
+// startModbus starts one listener per configured port and returns them
+// for diagnostics.
+func startModbus(ctx context.Context, lc *lifecycle, cfg *config.AppConfig, memories map[string]*core.Memory) []*modbus.Server {
+	var out []*modbus.Server
 
-	// Start one Modbus TCP listener per configured port
+	// Start one Modbus listener (TCP or UDP) per configured port
 	for port, policy := range cfg.Ports {
 		p := port
 		pol := policy
 		addr := fmt.Sprintf(":%d", p)
 
-		go func() {
-			log.Printf("Starting Modbus TCP listener on %s", addr)
-
-			err := modbus.Start(
-				addr,
-				func(unitID uint8, fc uint8) *core.Memory {
-					return resolver(p, unitID, fc)
-				},
-				pol.IPFilter.Allow, // ✅ CONFIG-DRIVEN
-				pol.IPFilter.Deny,  // ✅ CONFIG-DRIVEN
-				pol.MaxConnections, // 🔒 per-port hard cap
-			)
+		mc := modbus.Config{
+			Addr:           addr,
+			AllowIPs:       pol.IPFilter.Allow, // ✅ CONFIG-DRIVEN
+			DenyIPs:        pol.IPFilter.Deny,  // ✅ CONFIG-DRIVEN
+			MaxConnections: pol.MaxConnections, // 🔒 per-port hard cap
+			Framing:        modbus.Framing(pol.Framing),
+			Transport:      modbus.Transport(pol.Transport),
+			IdleTimeout:    pol.IdleTimeout(),    // 🔒 reap dead clients
+			RequestTimeout: pol.RequestTimeout(), // 🔒 bound slow clients
+			KeepAlive:      keepAlive(pol.KeepAlive),
+			PipelineDepth:  pol.PipelineDepth,
+			Policy:         requestPolicy(pol),
+		}
 
+		// 🔒 Modbus/TCP Security: mutual TLS + certificate roles
+		// (roles get their own gateway routes)
+		if pol.TLS != nil {
+			tlsCfg, err := modbus.NewServerTLSConfig(
+				pol.TLS.CertFile,
+				pol.TLS.KeyFile,
+				pol.TLS.ClientCAFile,
+			)
 			if err != nil {
+				log.Fatalf("ports.%d: %v", p, err)
+			}
+			mc.TLS = tlsCfg
+			mc.Roles = roleResolver(ctx, lc, cfg, memories, pol)
+		} else {
+			mc.Upstream = upstreamResolver(ctx, lc, cfg, pol)
+		}
+
+		log.Printf("Starting Modbus %s listener on %s", transportName(pol.Transport), addr)
+
+		srv, err := modbus.Listen(mc, policyResolver(cfg, memories, pol))
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		// Stop accepting, let transactions in flight finish
+		lc.onShutdown(ctx, "Modbus "+addr, srv.Shutdown)
+
+		go func() {
+			if err := srv.Serve(); !errors.Is(err, modbus.ErrServerClosed) {
 				log.Fatal(err)
 			}
 		}()
+
+		out = append(out, srv)
+	}
+
+	return out
+}
+
+// modbusStatus exposes listener counters to REST diagnostics.
+func modbusStatus(listeners []*modbus.Server) func() any {
+	return func() any {
+		out := make([]modbus.Stats, 0, len(listeners))
+		for _, srv := range listeners {
+			out = append(out, srv.Stats())
+		}
+		return out
+	}
+}
+
+// keepAlive maps the port keepalive settings; nil keeps the defaults.
+func keepAlive(k *config.KeepAliveConfig) *net.KeepAliveConfig {
+	if k == nil {
+		return nil
+	}
+
+	return &net.KeepAliveConfig{
+		Enable:   !k.Disabled,
+		Idle:     time.Duration(k.IdleMS) * time.Millisecond,
+		Interval: time.Duration(k.IntervalMS) * time.Millisecond,
+		Count:    k.Count,
+	}
+}
+
+// requestPolicy carries the parts of a port policy that are enforced
+// inside the Modbus request path.
+func requestPolicy(policy config.PortPolicy) modbus.Policy {
+	return modbus.Policy{
+		Access:    addressCheck(policy),
+		View:      portView(policy),
+		Broadcast: policy.Broadcast,
+	}
+}
+
+// portView builds the address remapping of a port (validated by config).
+func portView(policy config.PortPolicy) *modbus.View {
+	if len(policy.Views) == 0 {
+		return nil
+	}
+
+	windows := make([]modbus.Window, 0, len(policy.Views))
+	for _, w := range policy.Views {
+		area, _ := core.ParseArea(w.Area)
+		target, _ := core.ParseArea(w.TargetArea())
+
+		windows = append(windows, modbus.Window{
+			Area:   area,
+			Start:  w.Start,
+			Length: w.Length,
+			Target: target,
+			Offset: w.Target.Offset,
+		})
+	}
+	return modbus.NewView(windows)
+}
+
+// addressCheck exposes the port address ACL to the Modbus request path.
+func addressCheck(policy config.PortPolicy) modbus.AccessCheck {
+	if policy.AddressACL == nil {
+		return nil
+	}
+
+	return func(a modbus.Access) bool {
+		return policy.AllowsAddress(a.Area, a.Write, a.Addr, a.Count)
+	}
+}
+
+func transportName(t config.TransportMode) string {
+	if t == config.TransportUDP {
+		return "UDP"
 	}
+	return "TCP"
 }
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/config"
+	"modbus-memory-appliance/internal/modbus"
+)
+
+// Shutdown closes the listener's upstream pools.
+func TestUpstreamResolver_ClosesPoolsOnShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	// Echo one FC 06 request, then wait for the gateway to hang up
+	closed := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 12)
+		if _, err := io.ReadFull(conn, buf); err == nil {
+			conn.Write(buf)
+			io.ReadFull(conn, buf)
+		}
+		close(closed)
+	}()
+
+	cfg := &config.AppConfig{Routing: config.RoutingConfig{
+		Upstreams: config.UpstreamMap{
+			{First: 10, Last: 10, Upstream: config.Upstream{Address: ln.Addr().String()}},
+		},
+	}}
+	policy := config.PortPolicy{UnitIDs: config.UnitIDSelector{All: true}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc := newLifecycle(time.Second)
+
+	up, unitID := upstreamResolver(ctx, lc, cfg, policy)(10, 0x06)
+	if up == nil {
+		t.Fatal("unit 10 not routed upstream")
+	}
+	if _, err := up.Do(unitID, modbus.PDU{Function: 0x06, Data: []byte{0x00, 0x02, 0x12, 0x34}}); err != nil {
+		t.Fatal(err)
+	}
+
+	cancel()
+	if code := lc.wait(); code != exitOK {
+		t.Fatalf("expected exit %d, got %d", exitOK, code)
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("upstream connection still open after shutdown")
+	}
+}
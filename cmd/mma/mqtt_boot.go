@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"time"
 
@@ -9,7 +10,7 @@ import (
 	"modbus-memory-appliance/internal/mqtt"
 )
 
-func startMQTT(cfg *config.AppConfig, ingestSvc *ingest.Service) {
+func startMQTT(ctx context.Context, lc *lifecycle, cfg *config.AppConfig, ingestSvc *ingest.Service) {
 	if !cfg.MQTT.Enabled {
 		return
 	}
@@ -23,19 +24,26 @@ func startMQTT(cfg *config.AppConfig, ingestSvc *ingest.Service) {
 		Password: cfg.MQTT.Password,
 	}
 
-	go func() {
+	lc.run(func() {
 		for {
 			log.Printf("Starting MQTT subscriber (broker=%s)", mqttCfg.Broker)
 
-			_, err := mqtt.NewSubscriber(mqttCfg, ingestSvc)
+			sub, err := mqtt.NewSubscriber(mqttCfg, ingestSvc)
 			if err != nil {
 				log.Printf("MQTT unavailable: %v", err)
-				time.Sleep(10 * time.Second)
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(10 * time.Second):
+				}
 				continue
 			}
 
-			log.Printf("MQTT subscriber exited, restarting...")
-			time.Sleep(5 * time.Second)
+			// The client reconnects by itself from here on
+			<-ctx.Done()
+			sub.Close()
+			return
 		}
-	}()
+	})
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"time"
+
+	"modbus-memory-appliance/internal/config"
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/persist"
+)
+
+// restoreMemories loads retained snapshots. It runs before any
+// listener starts, so no client sees the zeroed memory. A missing,
+// corrupt or mismatched snapshot leaves the memory empty. auto_seal
+// only seals a memory whose snapshot was itself taken in RUN: one taken
+// in PRE-RUN may hold values that were still being loaded.
+func restoreMemories(cfg *config.AppConfig, memories map[string]*core.Memory) {
+	for id, block := range cfg.Memory.Memories {
+		p := block.Persistence
+		if p == nil {
+			continue
+		}
+		mem := memories[id]
+
+		snap, err := persist.Load(p.Path)
+		if err == nil {
+			err = mem.Restore(snap)
+		}
+
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Printf("Persistence: memory=%s no snapshot at %s, starting empty", id, p.Path)
+			continue
+		case err != nil:
+			log.Printf("Persistence: memory=%s snapshot %s not restored, starting empty: %v", id, p.Path, err)
+			continue
+		}
+
+		log.Printf("Persistence: memory=%s restored %s (generation=%d)", id, p.Path, snap.Generation)
+
+		if p.AutoSeal {
+			if snap.Sealed {
+				mem.Seal()
+			} else {
+				log.Printf("Persistence: memory=%s snapshot was taken in PRE-RUN, not sealing", id)
+			}
+		}
+	}
+}
+
+// startPersistence writes periodic snapshots until ctx is cancelled.
+func startPersistence(ctx context.Context, lc *lifecycle, cfg *config.AppConfig, memories map[string]*core.Memory) {
+	for id, block := range cfg.Memory.Memories {
+		p := block.Persistence
+		if p == nil || p.Interval() == 0 {
+			continue
+		}
+		mem := memories[id]
+
+		log.Printf("Starting persistence for memory %s (%s every %s)", id, p.Path, p.Interval())
+		lc.run(func() { snapshotEvery(ctx, id, p.Path, p.Interval(), mem) })
+	}
+}
+
+// snapshotEvery saves mem each interval in which it changed.
+func snapshotEvery(ctx context.Context, id, path string, interval time.Duration, mem *core.Memory) {
+	saved := mem.Generation()
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		if mem.Generation() == saved {
+			continue
+		}
+		snap := mem.Snapshot()
+		if err := persist.Save(path, snap); err != nil {
+			log.Printf("Persistence: memory=%s snapshot failed: %v", id, err)
+			continue
+		}
+		saved = snap.Generation
+	}
+}
+
+// snapshotOnShutdown saves every memory configured for it. It runs
+// after the transports drained, so the last writes are retained.
+func snapshotOnShutdown(cfg *config.AppConfig, memories map[string]*core.Memory) {
+	for id, block := range cfg.Memory.Memories {
+		p := block.Persistence
+		if p == nil || !p.SnapshotOnShutdown {
+			continue
+		}
+
+		if err := persist.Save(p.Path, memories[id].Snapshot()); err != nil {
+			log.Printf("Persistence: memory=%s shutdown snapshot failed: %v", id, err)
+			continue
+		}
+		log.Printf("Persistence: memory=%s saved %s", id, p.Path)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"modbus-memory-appliance/internal/config"
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/persist"
+)
+
+func newSealingMemory() *core.Memory {
+	mem := core.NewMemory(4, 4, 4, 4)
+	mem.SetStateSealing(true, 3)
+	return mem
+}
+
+// auto_seal seals only a memory whose snapshot was taken in RUN.
+func TestRestoreMemories_AutoSealFollowsSnapshot(t *testing.T) {
+	tests := []struct {
+		name   string
+		sealed bool
+	}{
+		{"taken in RUN", true},
+		{"taken in PRE-RUN", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plant.snap")
+
+			before := newSealingMemory()
+			_ = before.WriteHoldingRegs(0, []uint16{42})
+			if tt.sealed {
+				before.Seal()
+			}
+			if err := persist.Save(path, before.Snapshot()); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &config.AppConfig{Memory: config.MemoryConfig{
+				Memories: map[string]config.MemoryBlock{
+					"plant": {Persistence: &config.PersistenceConfig{Path: path, AutoSeal: true}},
+				},
+			}}
+			after := newSealingMemory()
+			restoreMemories(cfg, map[string]*core.Memory{"plant": after})
+
+			if hr, _ := after.ReadHoldingRegs(0, 1); hr[0] != 42 {
+				t.Fatalf("expected restored 42, got %d", hr[0])
+			}
+			if after.IsPreRun() == tt.sealed {
+				t.Fatalf("expected sealed=%v after restore", tt.sealed)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"modbus-memory-appliance/internal/config"
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/poller"
+)
+
+// startPollers starts one poller per configured device and returns them
+// for diagnostics.
+func startPollers(ctx context.Context, lc *lifecycle, cfg *config.AppConfig, memories map[string]*core.Memory) []*poller.Poller {
+	var out []*poller.Poller
+
+	for name, pc := range cfg.Pollers {
+		reads := make([]poller.Read, 0, len(pc.Reads))
+		for _, r := range pc.Reads {
+			// Area names are validated by config
+			area, _ := core.ParseArea(r.Area)
+			target, _ := core.ParseArea(r.Target)
+
+			reads = append(reads, poller.Read{
+				Area:          area,
+				Address:       r.Address,
+				Count:         r.Count,
+				Target:        target,
+				TargetAddress: r.TargetAddress,
+			})
+		}
+
+		p := poller.New(poller.Config{
+			Name:     name,
+			Address:  pc.Address,
+			UnitID:   pc.UnitID,
+			Memory:   pc.Memory,
+			Interval: pc.Interval(),
+			Timeout:  pc.Timeout(),
+			Reads:    reads,
+		}, memories[pc.Memory])
+
+		log.Printf("Starting poller %s (%s every %s)", name, pc.Address, pc.Interval())
+		lc.run(func() { p.Run(ctx) })
+
+		out = append(out, p)
+	}
+
+	return out
+}
+
+// pollerStatus exposes poller counters to REST diagnostics.
+func pollerStatus(pollers []*poller.Poller) func() any {
+	return func() any {
+		out := make([]poller.Status, 0, len(pollers))
+		for _, p := range pollers {
+			out = append(out, p.Status())
+		}
+		return out
+	}
+}
@@ -17,30 +17,31 @@ import (
 )
 
 // ---- Adapter: core.Memory -> rawingest.RawWritableMemory ----
+// Raw Ingest addresses are external; the adapter applies AreaConfig.Start.
 
 type rawIngestMemoryAdapter struct {
 	mem *core.Memory
 }
 
 func (a *rawIngestMemoryAdapter) WriteCoils(addr uint16, v []bool) error {
-	return a.mem.WriteCoils(int(addr), v)
+	return a.mem.From(core.SourceRawIngest).WriteCoils(a.mem.ToInternal(core.AreaCoils, int(addr)), v)
 }
 
 func (a *rawIngestMemoryAdapter) WriteDiscreteInputs(addr uint16, v []bool) error {
-	return a.mem.WriteDiscreteInputs(int(addr), v)
+	return a.mem.From(core.SourceRawIngest).WriteDiscreteInputs(a.mem.ToInternal(core.AreaDiscreteInputs, int(addr)), v)
 }
 
 func (a *rawIngestMemoryAdapter) WriteHoldingRegisters(addr uint16, v []uint16) error {
-	return a.mem.WriteHoldingRegs(int(addr), v)
+	return a.mem.From(core.SourceRawIngest).WriteHoldingRegs(a.mem.ToInternal(core.AreaHoldingRegs, int(addr)), v)
 }
 
 func (a *rawIngestMemoryAdapter) WriteInputRegisters(addr uint16, v []uint16) error {
-	return a.mem.WriteInputRegs(int(addr), v)
+	return a.mem.From(core.SourceRawIngest).WriteInputRegs(a.mem.ToInternal(core.AreaInputRegs, int(addr)), v)
 }
 
 // ---- Boot wiring ----
 
-func startRawIngest(cfg *config.AppConfig, memories map[string]*core.Memory) {
+func startRawIngest(ctx context.Context, lc *lifecycle, cfg *config.AppConfig, memories map[string]*core.Memory) {
 
 	//start debug
 	log.Printf("[DEBUG] RawIngest enabled=%v listen=%s",
@@ -56,11 +57,18 @@ func startRawIngest(cfg *config.AppConfig, memories map[string]*core.Memory) {
 	}
 
 	// Resolver: raw ingest memory_id (uint16) -> memories map key.
-	// Raw ingest writes directly to memory; no RoutingConfig is used here.
+	// Raw ingest writes directly to memory; the unit ID map is not used.
+	// Only routing.mode default_fallback applies: unknown IDs land on
+	// the default memory.
+	fallback := cfg.FallbackMemoryID()
+
 	resolver := rawingest.MemoryResolverFunc(func(id uint16) (rawingest.RawWritableMemory, bool) {
 		key := fmt.Sprintf("%d", id)
 
 		mem, ok := memories[key]
+		if !ok && fallback != "" {
+			mem, ok = memories[fallback]
+		}
 		if !ok {
 			return nil, false
 		}
@@ -80,10 +88,10 @@ func startRawIngest(cfg *config.AppConfig, memories map[string]*core.Memory) {
 		log.Fatal(err)
 	}
 
-	go func() {
+	lc.run(func() {
 		log.Printf("Starting Raw Ingest listener on %s", cfg.RawIngest.Listen)
-		if err := srv.Start(context.Background()); err != nil {
+		if err := srv.Start(ctx); err != nil {
 			log.Fatal(err)
 		}
-	}()
+	})
 }
@@ -1,19 +1,26 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 
 	"modbus-memory-appliance/internal/config"
 	"modbus-memory-appliance/internal/core"
 	"modbus-memory-appliance/internal/ingest"
+	"modbus-memory-appliance/internal/modbus"
+	"modbus-memory-appliance/internal/poller"
 	"modbus-memory-appliance/internal/rest"
 )
 
 func startREST(
+	ctx context.Context,
+	lc *lifecycle,
 	cfg *config.AppConfig,
 	memories map[string]*core.Memory,
 	ingestSvc *ingest.Service,
+	pollers []*poller.Poller,
+	listeners []*modbus.Server,
 ) {
 	// ---- config gate ----
 	if !cfg.REST.Enabled {
@@ -29,8 +36,11 @@ func startREST(
 	// ---- handlers ----
 	handlers := &rest.Handlers{
 		MemoryConfig:      &cfg.Memory,
+		Memories:          memories,
 		Ingest:            ingestSvc,
 		Stats:             rest.NewStats(),
+		PollerStatus:      pollerStatus(pollers),
+		ModbusStatus:      modbusStatus(listeners),
 		EnableIngest:      true,
 		EnableRead:        true,
 		EnableDiagnostics: true,
@@ -51,15 +61,18 @@ func startREST(
 	}
 
 	// ---- start server ----
+	srv := rest.NewServer(
+		cfg.REST.Address,
+		handlers,
+		authMiddleware, // 🔐 AUTH IS NOW ACTIVE
+	)
+
+	// In-flight requests finish before the server stops
+	lc.onShutdown(ctx, "REST", srv.Shutdown)
+
 	go func() {
 		log.Printf("Starting REST server on %s", cfg.REST.Address)
 
-		srv := rest.NewServer(
-			cfg.REST.Address,
-			handlers,
-			authMiddleware, // 🔐 AUTH IS NOW ACTIVE
-		)
-
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("REST server error: %v", err)
 		}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"modbus-memory-appliance/internal/config"
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/modbus"
+)
+
+func startSerial(ctx context.Context, lc *lifecycle, cfg *config.AppConfig, memories map[string]*core.Memory) {
+	if len(cfg.Serial) == 0 {
+		return
+	}
+
+	// Start one Modbus slave per configured serial device
+	for device, line := range cfg.Serial {
+		dev := device
+		l := line
+
+		routing := cfg.RoutingFor(l.PortPolicy)
+		fallback := routing.FallbackMemoryID(cfg.Memory)
+
+		// Only answer for unit IDs this line owns; other addresses
+		// belong to other slaves on the bus.
+		addressed := func(unitID uint8) bool {
+			_, routed := routing.Route(unitID, fallback)
+			return (routed || routing.Forwards(unitID)) && l.AllowsUnitID(unitID)
+		}
+
+		pol := requestPolicy(l.PortPolicy)
+		pol.Upstream = upstreamResolver(ctx, lc, cfg, l.PortPolicy)
+
+		lc.run(func() {
+			log.Printf("Starting Modbus serial slave on %s", dev)
+
+			err := modbus.StartSerial(
+				ctx,
+				modbus.SerialConfig{
+					Device:         dev,
+					BaudRate:       l.BaudRate,
+					DataBits:       l.DataBits,
+					Parity:         modbus.Parity(l.Parity),
+					StopBits:       l.StopBits,
+					SilentInterval: time.Duration(l.SilentIntervalUS) * time.Microsecond,
+					Framing:        modbus.Framing(l.Framing),
+					Addressed:      addressed,
+					Policy:         pol,
+				},
+				policyResolver(cfg, memories, l.PortPolicy),
+			)
+
+			// A failed line stops only itself: the other transports keep
+			// running and shutdown still drains and snapshots normally.
+			if err != nil {
+				log.Printf("Modbus serial slave on %s stopped: %v", dev, err)
+			}
+		})
+	}
+}
@@ -2,7 +2,11 @@
 
 package main
 
-import "golang.org/x/sys/windows/svc"
+import (
+	"context"
+
+	"golang.org/x/sys/windows/svc"
+)
 
 // mmaService implements the Windows Service interface.
 // It acts only as a lifecycle bridge between SCM and appMain().
@@ -15,7 +19,11 @@ func (m *mmaService) Execute(args []string, r <-chan svc.ChangeRequest, s chan<-
 	s <- svc.Status{State: svc.StartPending}
 
 	// Start the normal application runtime (same as console/Docker)
-	go appMain()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	exit := make(chan int, 1)
+	go func() { exit <- appMain(ctx) }()
 
 	// Notify SCM that service is now running
 	s <- svc.Status{
@@ -29,8 +37,10 @@ func (m *mmaService) Execute(args []string, r <-chan svc.ChangeRequest, s chan<-
 		c := <-r
 		switch c.Cmd {
 		case svc.Stop, svc.Shutdown:
+			// Drain transports like SIGTERM does on Linux
 			s <- svc.Status{State: svc.StopPending}
-			return false, 0
+			cancel()
+			return false, uint32(<-exit)
 		}
 	}
 }
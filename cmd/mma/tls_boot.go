@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+
+	"modbus-memory-appliance/internal/config"
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/modbus"
+)
+
+// roleResolver layers the role policy from the client certificate on top
+// of the port policy: a request must pass both.
+func roleResolver(
+	ctx context.Context,
+	lc *lifecycle,
+	cfg *config.AppConfig,
+	memories map[string]*core.Memory,
+	policy config.PortPolicy,
+) modbus.RoleResolver {
+	port := policyResolver(cfg, memories, policy)
+	portUpstream := upstreamResolver(ctx, lc, cfg, policy)
+
+	return func(role string) (modbus.MemoryResolver, modbus.UpstreamResolver) {
+		rp, ok := policy.TLS.Roles[role]
+		if !ok {
+			return nil, nil
+		}
+		// Roles route through the port's table, not the global one
+		rpol := rp.Policy()
+		rpol.Routing = policy.Routing
+		scoped := policyResolver(cfg, memories, rpol)
+
+		resolve := func(unitID uint8, fc uint8) *core.Memory {
+			if scoped(unitID, fc) == nil {
+				return nil
+			}
+			return port(unitID, fc)
+		}
+
+		if portUpstream == nil {
+			return resolve, nil
+		}
+
+		// Upstream devices share the port's connection pools
+		upstream := func(unitID uint8, fc uint8) (*modbus.Upstream, uint8) {
+			if !rpol.AllowsUnitID(unitID) || !rpol.AllowsFunctionCode(fc) {
+				return nil, 0
+			}
+			return portUpstream(unitID, fc)
+		}
+		return resolve, upstream
+	}
+}
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// AddressACL restricts the address ranges a port may read or write,
+// per area. Addresses are the ones on the wire: view addresses for an
+// area with view windows on the port, otherwise external
+// (AreaConfig.Start based). Areas without rules keep the port's access
+// mode only.
+type AddressACL struct {
+	Coils            *AreaACL `yaml:"coils,omitempty"`
+	DiscreteInputs   *AreaACL `yaml:"discrete_inputs,omitempty"`
+	HoldingRegisters *AreaACL `yaml:"holding_registers,omitempty"`
+	InputRegisters   *AreaACL `yaml:"input_registers,omitempty"`
+}
+
+// AreaACL holds separate rules for reads and writes of one area.
+type AreaACL struct {
+	Read  *RangeRules `yaml:"read,omitempty"`
+	Write *RangeRules `yaml:"write,omitempty"`
+}
+
+// RangeRules follows FunctionCodeACL: deny wins, and a non-empty allow
+// list must cover the whole request.
+type RangeRules struct {
+	Allow []AddressRange `yaml:"allow,omitempty"`
+	Deny  []AddressRange `yaml:"deny,omitempty"`
+}
+
+// AddressRange is an inclusive address range ("100-149" or "7").
+type AddressRange struct {
+	First int
+	Last  int
+}
+
+func (r AddressRange) String() string {
+	if r.First == r.Last {
+		return strconv.Itoa(r.First)
+	}
+	return fmt.Sprintf("%d-%d", r.First, r.Last)
+}
+
+func (r *AddressRange) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.ScalarNode {
+		return fmt.Errorf("address range must be \"first-last\" or a single address")
+	}
+
+	lo, hi, isRange := strings.Cut(n.Value, "-")
+	first, err := strconv.Atoi(strings.TrimSpace(lo))
+	if err != nil {
+		return fmt.Errorf("address range %q: invalid start", n.Value)
+	}
+	last := first
+	if isRange {
+		if last, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil {
+			return fmt.Errorf("address range %q: invalid end", n.Value)
+		}
+	}
+
+	*r = AddressRange{First: first, Last: last}
+	return nil
+}
+
+func (r AddressRange) MarshalYAML() (any, error) {
+	return r.String(), nil
+}
+
+// area returns the rules for one area, or nil.
+func (a *AddressACL) area(area core.Area) *AreaACL {
+	switch area {
+	case core.AreaCoils:
+		return a.Coils
+	case core.AreaDiscreteInputs:
+		return a.DiscreteInputs
+	case core.AreaHoldingRegs:
+		return a.HoldingRegisters
+	case core.AreaInputRegs:
+		return a.InputRegisters
+	}
+	return nil
+}
+
+// AllowsAddress returns true if count addresses from addr may be read
+// (or written) in an area under the port's address ACL.
+func (p PortPolicy) AllowsAddress(area core.Area, write bool, addr, count int) bool {
+	if p.AddressACL == nil {
+		return true
+	}
+
+	acl := p.AddressACL.area(area)
+	if acl == nil {
+		return true
+	}
+
+	rules := acl.Read
+	if write {
+		rules = acl.Write
+	}
+	if rules == nil {
+		return true
+	}
+
+	last := addr + count - 1
+
+	// Deny list wins: any overlap rejects the request
+	for _, d := range rules.Deny {
+		if addr <= d.Last && d.First <= last {
+			return false
+		}
+	}
+
+	// Allow list (if present) must cover every address
+	if len(rules.Allow) > 0 {
+		return covered(rules.Allow, addr, last)
+	}
+
+	return true
+}
+
+// covered reports whether the allow ranges together cover first..last.
+func covered(allow []AddressRange, first, last int) bool {
+	pos := first
+	for pos <= last {
+		next := pos
+		for _, a := range allow {
+			if a.First <= pos && a.Last >= pos && a.Last+1 > next {
+				next = a.Last + 1
+			}
+		}
+		if next == pos {
+			return false
+		}
+		pos = next
+	}
+	return true
+}
+
+// validateAddressACL checks every range against the addresses the port
+// serves: a rule must fall inside one view window of its area or, for
+// an area without windows, inside the area of at least one memory the
+// port exposes. Views must be validated first.
+func (c *AppConfig) validateAddressACL(name string, p PortPolicy) error {
+	areas := []struct {
+		key  string
+		acl  *AreaACL
+		id   core.Area
+		area func(MemoryBlock) AreaConfig
+	}{
+		{"coils", p.AddressACL.Coils, core.AreaCoils, func(m MemoryBlock) AreaConfig { return m.Coils }},
+		{"discrete_inputs", p.AddressACL.DiscreteInputs, core.AreaDiscreteInputs, func(m MemoryBlock) AreaConfig { return m.DiscreteInputs }},
+		{"holding_registers", p.AddressACL.HoldingRegisters, core.AreaHoldingRegs, func(m MemoryBlock) AreaConfig { return m.HoldingRegisters }},
+		{"input_registers", p.AddressACL.InputRegisters, core.AreaInputRegs, func(m MemoryBlock) AreaConfig { return m.InputRegisters }},
+	}
+
+	for _, a := range areas {
+		if a.acl == nil {
+			continue
+		}
+
+		ops := []struct {
+			key   string
+			rules *RangeRules
+		}{{"read", a.acl.Read}, {"write", a.acl.Write}}
+
+		for _, op := range ops {
+			rules := op.rules
+			if rules == nil {
+				continue
+			}
+
+			path := fmt.Sprintf("%s.address_acl.%s.%s", name, a.key, op.key)
+			for _, r := range append(append([]AddressRange(nil), rules.Allow...), rules.Deny...) {
+				if r.First < 0 || r.Last > 65535 || r.First > r.Last {
+					return fmt.Errorf("%s: invalid range %s", path, r)
+				}
+				if windowed, ok := rangeInView(p, a.id, r); windowed {
+					if !ok {
+						return fmt.Errorf("%s: range %s outside every view window of the area", path, r)
+					}
+				} else if !c.rangeInAnyMemory(p, a.area, r) {
+					return fmt.Errorf("%s: range %s outside every exposed memory", path, r)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// rangeInView reports whether the port has view windows for area and,
+// if so, whether r falls inside one of them.
+func rangeInView(p PortPolicy, area core.Area, r AddressRange) (windowed, ok bool) {
+	for _, w := range p.Views {
+		if a, _ := core.ParseArea(w.Area); a != area {
+			continue
+		}
+		windowed = true
+		if r.First >= w.Start && r.Last < w.Start+w.Length {
+			return true, true
+		}
+	}
+	return windowed, false
+}
+
+func (c *AppConfig) rangeInAnyMemory(
+	p PortPolicy,
+	area func(MemoryBlock) AreaConfig,
+	r AddressRange,
+) bool {
+	for memID, block := range c.Memory.Memories {
+		if !p.AllowsMemory(memID) {
+			continue
+		}
+		w := area(block)
+		if r.First >= w.Start && r.Last < w.Start+w.Size {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestAddressACL_Unmarshal(t *testing.T) {
+	src := `
+unit_ids: all
+memories: all
+access: read-write
+address_acl:
+  holding_registers:
+    write:
+      allow: ["100-149"]
+      deny: [120]
+`
+	var p PortPolicy
+	if err := yaml.Unmarshal([]byte(src), &p); err != nil {
+		t.Fatal(err)
+	}
+
+	rules := p.AddressACL.HoldingRegisters.Write
+	if len(rules.Allow) != 1 || rules.Allow[0] != (AddressRange{First: 100, Last: 149}) {
+		t.Fatalf("unexpected allow rules: %+v", rules.Allow)
+	}
+	if len(rules.Deny) != 1 || rules.Deny[0] != (AddressRange{First: 120, Last: 120}) {
+		t.Fatalf("unexpected deny rules: %+v", rules.Deny)
+	}
+}
+
+func TestAllowsAddress(t *testing.T) {
+	p := PortPolicy{
+		Access: AccessReadWrite,
+		AddressACL: &AddressACL{
+			HoldingRegisters: &AreaACL{
+				Write: &RangeRules{
+					Allow: []AddressRange{{100, 139}, {140, 149}},
+					Deny:  []AddressRange{{120, 120}},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		name  string
+		write bool
+		addr  int
+		count int
+		want  bool
+	}{
+		{"read anywhere", false, 0, 125, true},
+		{"write inside", true, 100, 10, true},
+		{"write across adjacent allows", true, 135, 10, true},
+		{"write past allow", true, 145, 10, false},
+		{"write below allow", true, 99, 1, false},
+		{"write touching deny", true, 115, 10, false},
+	}
+
+	for _, tc := range cases {
+		if got := p.AllowsAddress(core.AreaHoldingRegs, tc.write, tc.addr, tc.count); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	// Other areas are unrestricted
+	if !p.AllowsAddress(core.AreaCoils, true, 0, 8) {
+		t.Fatal("expected coils unrestricted")
+	}
+}
+
+func TestValidatePorts_AddressACL(t *testing.T) {
+	cfg := routingTestConfig(RoutingStrict) // holding registers 0..7
+	cfg.Routing.UnitIDMap = UnitIDMap{{First: 1, Last: 1, Memory: "plant_a"}}
+
+	port := func(r AddressRange) Ports {
+		return Ports{502: {
+			UnitIDs:  UnitIDSelector{All: true},
+			Memories: MemorySelector{All: true},
+			Access:   AccessReadWrite,
+			AddressACL: &AddressACL{
+				HoldingRegisters: &AreaACL{Write: &RangeRules{Allow: []AddressRange{r}}},
+			},
+		}}
+	}
+
+	cfg.Ports = port(AddressRange{First: 2, Last: 5})
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected valid ACL, got %v", err)
+	}
+
+	for _, r := range []AddressRange{{First: 5, Last: 8}, {First: 6, Last: 2}, {First: -1, Last: 0}} {
+		cfg.Ports = port(r)
+		if err := cfg.ValidatePorts(); err == nil {
+			t.Fatalf("range %s: expected validation error", r)
+		}
+	}
+}
+
+// With a view, ACL ranges are view (wire) addresses.
+func TestValidatePorts_AddressACLWithView(t *testing.T) {
+	cfg := routingTestConfig(RoutingStrict) // holding registers 0..7
+	cfg.Routing.UnitIDMap = UnitIDMap{{First: 1, Last: 1, Memory: "plant_a"}}
+
+	port := func(r AddressRange) Ports {
+		return Ports{502: {
+			UnitIDs:  UnitIDSelector{All: true},
+			Memories: MemorySelector{All: true},
+			Access:   AccessReadWrite,
+			Views: []ViewWindow{
+				{Area: "holding_registers", Start: 3000, Length: 8},
+			},
+			AddressACL: &AddressACL{
+				HoldingRegisters: &AreaACL{Write: &RangeRules{Allow: []AddressRange{r}}},
+			},
+		}}
+	}
+
+	cfg.Ports = port(AddressRange{First: 3002, Last: 3005})
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected view range to be valid, got %v", err)
+	}
+
+	// Memory addresses are not on the wire of this port
+	for _, r := range []AddressRange{{First: 2, Last: 5}, {First: 3005, Last: 3008}} {
+		cfg.Ports = port(r)
+		if err := cfg.ValidatePorts(); err == nil || !strings.Contains(err.Error(), "view window") {
+			t.Fatalf("range %s: expected view window error, got %v", r, err)
+		}
+	}
+
+	// Areas without windows keep memory addresses
+	cfg.Ports = port(AddressRange{First: 3002, Last: 3005})
+	p := cfg.Ports[502]
+	p.AddressACL.Coils = &AreaACL{Read: &RangeRules{Deny: []AddressRange{{First: 0, Last: 3}}}}
+	cfg.Ports[502] = p
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected coil range to be valid, got %v", err)
+	}
+}
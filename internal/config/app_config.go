@@ -7,7 +7,10 @@ type AppConfig struct {
 	Memory    MemoryConfig
 	Routing   RoutingConfig
 	Ports     Ports
+	Serial    SerialPorts     `yaml:"serial"`
 	RawIngest RawIngestConfig `yaml:"raw_ingest"`
+	Pollers   Pollers         `yaml:"pollers"`
+	Shutdown  ShutdownConfig  `yaml:"shutdown"`
 
 	// Ingest / control plane
 	REST RESTConfig
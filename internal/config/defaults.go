@@ -8,6 +8,10 @@ import "fmt"
 // This is a mechanical safety guard, not a security feature.
 const DefaultMaxConnections = 32
 
+// MaxPipelineDepth bounds the requests one connection may have handled
+// concurrently.
+const MaxPipelineDepth = 64
+
 // DefaultMemoryID returns the memory ID marked as default.
 func (c *MemoryConfig) DefaultMemoryID() (string, error) {
 	for id, mem := range c.Memories {
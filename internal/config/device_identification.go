@@ -0,0 +1,102 @@
+// internal/config/device_identification.go
+package config
+
+import "fmt"
+
+// =========================
+// Device Identification (FC 0x2B / MEI 0x0E)
+// =========================
+
+// Object IDs defined by the Modbus Application Protocol.
+const (
+	DeviceIDVendorName          uint8 = 0x00
+	DeviceIDProductCode         uint8 = 0x01
+	DeviceIDMajorMinorRevision  uint8 = 0x02
+	DeviceIDVendorURL           uint8 = 0x03
+	DeviceIDProductName         uint8 = 0x04
+	DeviceIDModelName           uint8 = 0x05
+	DeviceIDUserApplicationName uint8 = 0x06
+
+	// Extended objects are private and start at 0x80.
+	DeviceIDExtendedFirst uint8 = 0x80
+)
+
+// maxDeviceIDObjectLen is the largest object value that fits in a
+// single Read Device Identification response PDU (253 bytes).
+const maxDeviceIDObjectLen = 244
+
+type DeviceIdentificationConfig struct {
+	// Basic (mandatory)
+	VendorName  string `yaml:"vendor_name"`
+	ProductCode string `yaml:"product_code"`
+	Revision    string `yaml:"revision"`
+
+	// Regular (optional)
+	VendorURL           string `yaml:"vendor_url,omitempty"`
+	ProductName         string `yaml:"product_name,omitempty"`
+	ModelName           string `yaml:"model_name,omitempty"`
+	UserApplicationName string `yaml:"user_application_name,omitempty"`
+
+	// Extended (optional, object IDs 0x80..0xFF)
+	Extended map[uint8]string `yaml:"extended,omitempty"`
+}
+
+// Objects returns the configured identification objects keyed by object ID.
+// Empty optional objects are omitted.
+func (d *DeviceIdentificationConfig) Objects() map[uint8]string {
+	out := map[uint8]string{
+		DeviceIDVendorName:         d.VendorName,
+		DeviceIDProductCode:        d.ProductCode,
+		DeviceIDMajorMinorRevision: d.Revision,
+	}
+
+	optional := map[uint8]string{
+		DeviceIDVendorURL:           d.VendorURL,
+		DeviceIDProductName:         d.ProductName,
+		DeviceIDModelName:           d.ModelName,
+		DeviceIDUserApplicationName: d.UserApplicationName,
+	}
+	for id, v := range optional {
+		if v != "" {
+			out[id] = v
+		}
+	}
+
+	for id, v := range d.Extended {
+		out[id] = v
+	}
+
+	return out
+}
+
+func validateDeviceIdentification(d *DeviceIdentificationConfig, memName string) error {
+	if d.VendorName == "" || d.ProductCode == "" || d.Revision == "" {
+		return fmt.Errorf(
+			"memory '%s': device_identification requires vendor_name, product_code and revision",
+			memName,
+		)
+	}
+
+	for id := range d.Extended {
+		if id < DeviceIDExtendedFirst {
+			return fmt.Errorf(
+				"memory '%s': device_identification extended object id 0x%02X must be >= 0x80",
+				memName,
+				id,
+			)
+		}
+	}
+
+	for id, v := range d.Objects() {
+		if len(v) > maxDeviceIDObjectLen {
+			return fmt.Errorf(
+				"memory '%s': device_identification object 0x%02X exceeds %d bytes",
+				memName,
+				id,
+				maxDeviceIDObjectLen,
+			)
+		}
+	}
+
+	return nil
+}
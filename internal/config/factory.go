@@ -27,13 +27,22 @@ func BuildMemories(cfg *MemoryConfig) (map[string]*core.Memory, error) {
 			)
 		}
 
+		// =========================
+		// Apply External Addressing (AreaConfig.Start)
+		// =========================
+		mem.SetAddressBase(core.AreaCoils, block.Coils.Start)
+		mem.SetAddressBase(core.AreaDiscreteInputs, block.DiscreteInputs.Start)
+		mem.SetAddressBase(core.AreaHoldingRegs, block.HoldingRegisters.Start)
+		mem.SetAddressBase(core.AreaInputRegs, block.InputRegisters.Start)
+
 		// =========================
 		// Apply State Sealing (optional, per memory)
 		// =========================
 		if block.StateSealing != nil && block.StateSealing.Enable {
+			// Gate address is external, like every other address
 			mem.SetStateSealing(
 				true,
-				block.StateSealing.Gate.Address,
+				mem.ToInternal(core.AreaDiscreteInputs, block.StateSealing.Gate.Address),
 			)
 
 			fmt.Printf(
@@ -49,6 +58,23 @@ func BuildMemories(cfg *MemoryConfig) (map[string]*core.Memory, error) {
 			)
 		}
 
+		// =========================
+		// Apply Device Identification (optional, per memory)
+		// =========================
+		if block.DeviceIdentification != nil {
+			mem.SetDeviceIdentity(block.DeviceIdentification.Objects())
+		}
+
+		// =========================
+		// Apply Generation Register (optional, per memory)
+		// =========================
+		if block.GenerationRegister != nil {
+			idx := mem.ToInternal(core.AreaInputRegs, *block.GenerationRegister)
+			if err := mem.SetGenerationRegister(idx); err != nil {
+				return nil, fmt.Errorf("memory '%s': generation_register: %w", memID, err)
+			}
+		}
+
 		memories[memID] = mem
 	}
 
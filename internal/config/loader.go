@@ -66,8 +66,8 @@ func writeDefaultConfig(path string) error {
 			},
 		},
 		Routing: RoutingConfig{
-			UnitIDMap: map[uint8]string{
-				1: "memory1",
+			UnitIDMap: UnitIDMap{
+				{First: 1, Last: 1, Memory: "memory1"},
 			},
 		},
 	}
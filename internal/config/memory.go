@@ -1,7 +1,11 @@
 // internal/config/memory.go
 package config
 
-import "fmt"
+import (
+	"fmt"
+
+	"modbus-memory-appliance/internal/core"
+)
 
 // =========================
 // Memory Configuration Root
@@ -24,6 +28,14 @@ type MemoryBlock struct {
 	InputRegisters   AreaConfig `yaml:"input_registers"`
 
 	StateSealing *StateSealingConfig `yaml:"state_sealing,omitempty"`
+
+	DeviceIdentification *DeviceIdentificationConfig `yaml:"device_identification,omitempty"`
+
+	// GenerationRegister exposes the write generation as 4 read-only
+	// input registers at this external address (64-bit, high word first).
+	GenerationRegister *int `yaml:"generation_register,omitempty"`
+
+	Persistence *PersistenceConfig `yaml:"persistence,omitempty"`
 }
 
 // =========================
@@ -40,7 +52,7 @@ type AreaConfig struct {
 // =========================
 
 type StateSealingConfig struct {
-	Enable bool `yaml:"enable"`
+	Enable bool       `yaml:"enable"`
 	Gate   GateConfig `yaml:"gate"`
 }
 
@@ -88,12 +100,30 @@ func (c *MemoryConfig) Validate() error {
 				return err
 			}
 		}
+
+		if mem.DeviceIdentification != nil {
+			if err := validateDeviceIdentification(mem.DeviceIdentification, name); err != nil {
+				return err
+			}
+		}
+
+		if err := validateGenerationRegister(mem, name); err != nil {
+			return err
+		}
+
+		if err := validatePersistence(mem, name); err != nil {
+			return err
+		}
 	}
 
 	if !hasDefault {
 		return fmt.Errorf("no default memory defined")
 	}
 
+	if err := validatePersistencePaths(c.Memories); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -112,9 +142,44 @@ func validateArea(a AreaConfig, areaName, memName string) error {
 			areaName,
 		)
 	}
+	// The whole window must be addressable on the wire (uint16)
+	if a.Start+a.Size > 65536 {
+		return fmt.Errorf(
+			"memory '%s': area '%s' start+size must be <= 65536",
+			memName,
+			areaName,
+		)
+	}
+	return nil
+}
+
+func validateGenerationRegister(mem MemoryBlock, memName string) error {
+	if mem.GenerationRegister == nil {
+		return nil
+	}
+
+	addr := *mem.GenerationRegister
+	ir := mem.InputRegisters
+	if addr < ir.Start || addr+core.GenerationRegisters > ir.Start+ir.Size {
+		return fmt.Errorf(
+			"memory '%s': generation_register %d..%d outside input_registers %d..%d",
+			memName,
+			addr,
+			addr+core.GenerationRegisters-1,
+			ir.Start,
+			ir.Start+ir.Size-1,
+		)
+	}
 	return nil
 }
 
+// coversGeneration reports whether the input register range
+// [addr, addr+count) overlaps the generation registers.
+func (m MemoryBlock) coversGeneration(addr, count int) bool {
+	g := m.GenerationRegister
+	return g != nil && addr < *g+core.GenerationRegisters && *g < addr+count
+}
+
 func validateStateSealing(mem MemoryBlock) error {
 	g := mem.StateSealing.Gate
 
@@ -129,5 +194,16 @@ func validateStateSealing(mem MemoryBlock) error {
 		)
 	}
 
+	// Gate address is external (discrete_inputs.start based)
+	di := mem.DiscreteInputs
+	if g.Address < di.Start || g.Address >= di.Start+di.Size {
+		return fmt.Errorf(
+			"state_sealing gate address %d outside discrete_inputs %d..%d",
+			g.Address,
+			di.Start,
+			di.Start+di.Size-1,
+		)
+	}
+
 	return nil
 }
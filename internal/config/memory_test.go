@@ -0,0 +1,80 @@
+package config
+
+import (
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func addressingTestConfig() *MemoryConfig {
+	area := AreaConfig{Start: 0, Size: 16}
+	return &MemoryConfig{
+		Memories: map[string]MemoryBlock{
+			"plant_a": {
+				Default:          true,
+				Coils:            area,
+				DiscreteInputs:   AreaConfig{Start: 10000, Size: 16},
+				HoldingRegisters: AreaConfig{Start: 40000, Size: 1000},
+				InputRegisters:   area,
+				StateSealing: &StateSealingConfig{
+					Enable: true,
+					Gate:   GateConfig{Area: "discrete_inputs", Address: 10015},
+				},
+			},
+		},
+	}
+}
+
+func TestValidate_AddressWindows(t *testing.T) {
+	if err := addressingTestConfig().Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cases := map[string]func(b *MemoryBlock){
+		"window past 65535": func(b *MemoryBlock) { b.HoldingRegisters.Start = 65000 },
+		"gate below window": func(b *MemoryBlock) { b.StateSealing.Gate.Address = 15 },
+		"gate past window":  func(b *MemoryBlock) { b.StateSealing.Gate.Address = 10016 },
+	}
+
+	for name, mutate := range cases {
+		cfg := addressingTestConfig()
+		b := cfg.Memories["plant_a"]
+		mutate(&b)
+		cfg.Memories["plant_a"] = b
+
+		if err := cfg.Validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestBuildMemories_AppliesAddressBase(t *testing.T) {
+	memories, err := BuildMemories(addressingTestConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	mem := memories["plant_a"]
+
+	if got := mem.ToInternal(core.AreaHoldingRegs, 40999); got != 999 {
+		t.Fatalf("expected holding 40999 at internal index 999, got %d", got)
+	}
+	if got := mem.AddressBase(core.AreaCoils); got != 0 {
+		t.Fatalf("expected coil base 0, got %d", got)
+	}
+	if got := mem.GateAddress(); got != 15 {
+		t.Fatalf("expected gate at internal index 15, got %d", got)
+	}
+}
+
+func TestValidate_GenerationRegister(t *testing.T) {
+	for addr, valid := range map[int]bool{0: true, 12: true, 13: false, -1: false} {
+		cfg := addressingTestConfig()
+		block := cfg.Memories["plant_a"]
+		block.GenerationRegister = &addr
+		cfg.Memories["plant_a"] = block
+
+		if err := cfg.Validate(); (err == nil) != valid {
+			t.Fatalf("generation_register %d: valid=%v, got %v", addr, valid, err)
+		}
+	}
+}
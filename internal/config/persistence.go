@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// PersistenceConfig retains a memory across restarts: snapshots are
+// written to Path and restored on boot, before any listener starts.
+type PersistenceConfig struct {
+	Path string `yaml:"path"`
+
+	// SnapshotOnShutdown writes a snapshot once every transport drained.
+	SnapshotOnShutdown bool `yaml:"snapshot_on_shutdown"`
+
+	// IntervalMS writes a snapshot periodically when the memory changed
+	// (0 = no periodic snapshots).
+	IntervalMS int `yaml:"interval_ms,omitempty"`
+
+	// AutoSeal moves a State Sealing memory to RUN when a valid snapshot
+	// taken in RUN was restored, without waiting for the gate.
+	AutoSeal bool `yaml:"auto_seal,omitempty"`
+}
+
+// Interval returns the periodic snapshot interval (0 = disabled).
+func (p PersistenceConfig) Interval() time.Duration {
+	return time.Duration(p.IntervalMS) * time.Millisecond
+}
+
+func validatePersistence(mem MemoryBlock, memName string) error {
+	p := mem.Persistence
+	if p == nil {
+		return nil
+	}
+
+	if p.Path == "" {
+		return fmt.Errorf("memory '%s': persistence path is required", memName)
+	}
+	if p.IntervalMS < 0 {
+		return fmt.Errorf("memory '%s': persistence interval_ms must be >= 0", memName)
+	}
+	if !p.SnapshotOnShutdown && p.IntervalMS == 0 {
+		return fmt.Errorf(
+			"memory '%s': persistence needs snapshot_on_shutdown or interval_ms",
+			memName,
+		)
+	}
+	if p.AutoSeal && (mem.StateSealing == nil || !mem.StateSealing.Enable) {
+		return fmt.Errorf(
+			"memory '%s': persistence auto_seal requires state_sealing",
+			memName,
+		)
+	}
+	return nil
+}
+
+// validatePersistencePaths rejects two memories sharing a snapshot file.
+func validatePersistencePaths(memories map[string]MemoryBlock) error {
+	owner := make(map[string]string)
+
+	// Sorted, so the error names the same pair on every run
+	for _, name := range slices.Sorted(maps.Keys(memories)) {
+		mem := memories[name]
+		if mem.Persistence == nil {
+			continue
+		}
+
+		path := filepath.Clean(mem.Persistence.Path)
+		if other, ok := owner[path]; ok {
+			return fmt.Errorf(
+				"memory '%s': persistence path %q already used by memory '%s'",
+				name,
+				path,
+				other,
+			)
+		}
+		owner[path] = name
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate_Persistence(t *testing.T) {
+	cases := map[string]struct {
+		p     PersistenceConfig
+		valid bool
+	}{
+		"shutdown only":   {PersistenceConfig{Path: "a.snap", SnapshotOnShutdown: true}, true},
+		"periodic only":   {PersistenceConfig{Path: "a.snap", IntervalMS: 1000}, true},
+		"auto seal":       {PersistenceConfig{Path: "a.snap", IntervalMS: 1000, AutoSeal: true}, true},
+		"missing path":    {PersistenceConfig{SnapshotOnShutdown: true}, false},
+		"never snapshots": {PersistenceConfig{Path: "a.snap"}, false},
+		"negative period": {PersistenceConfig{Path: "a.snap", IntervalMS: -1}, false},
+	}
+
+	for name, tc := range cases {
+		cfg := addressingTestConfig()
+		block := cfg.Memories["plant_a"]
+		block.Persistence = &tc.p
+		cfg.Memories["plant_a"] = block
+
+		if err := cfg.Validate(); (err == nil) != tc.valid {
+			t.Fatalf("%s: valid=%v, got %v", name, tc.valid, err)
+		}
+	}
+}
+
+func TestValidate_PersistenceAutoSealRequiresSealing(t *testing.T) {
+	cfg := addressingTestConfig()
+	block := cfg.Memories["plant_a"]
+	block.StateSealing = nil
+	block.Persistence = &PersistenceConfig{Path: "a.snap", SnapshotOnShutdown: true, AutoSeal: true}
+	cfg.Memories["plant_a"] = block
+
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "auto_seal") {
+		t.Fatalf("expected auto_seal error, got %v", err)
+	}
+}
+
+func TestValidate_PersistencePathsUnique(t *testing.T) {
+	cfg := addressingTestConfig()
+	a := cfg.Memories["plant_a"]
+	a.Persistence = &PersistenceConfig{Path: "data/mem.snap", SnapshotOnShutdown: true}
+	cfg.Memories["plant_a"] = a
+
+	b := a
+	b.Default = false
+	b.Persistence = &PersistenceConfig{Path: "data/../data/mem.snap", SnapshotOnShutdown: true}
+	cfg.Memories["plant_b"] = b
+
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "already used by memory 'plant_a'") {
+		t.Fatalf("expected shared path error, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// DefaultPollerTimeoutMS bounds one poll request when not configured.
+const DefaultPollerTimeoutMS = 1000
+
+// Pollers is keyed by poller name.
+type Pollers map[string]PollerConfig
+
+// PollerConfig reads ranges from a Modbus TCP device on a fixed cadence
+// and writes them into one memory. Pollers ingest field data, so they
+// write discrete inputs and input registers only.
+type PollerConfig struct {
+	Address    string     `yaml:"address"` // host:port
+	UnitID     uint8      `yaml:"unit_id"`
+	IntervalMS int        `yaml:"interval_ms"`
+	TimeoutMS  int        `yaml:"timeout_ms,omitempty"`
+	Memory     string     `yaml:"memory"`
+	Reads      []PollRead `yaml:"reads"`
+}
+
+// PollRead copies Count values from the device into the memory.
+type PollRead struct {
+	Area          string `yaml:"area"`    // device area
+	Address       int    `yaml:"address"` // device address
+	Count         int    `yaml:"count"`
+	Target        string `yaml:"target"`         // memory area
+	TargetAddress int    `yaml:"target_address"` // external memory address
+}
+
+// Interval returns the poll cadence.
+func (p PollerConfig) Interval() time.Duration {
+	return time.Duration(p.IntervalMS) * time.Millisecond
+}
+
+// Timeout bounds one request, connection setup included.
+func (p PollerConfig) Timeout() time.Duration {
+	if p.TimeoutMS <= 0 {
+		return DefaultPollerTimeoutMS * time.Millisecond
+	}
+	return time.Duration(p.TimeoutMS) * time.Millisecond
+}
+
+// pollTargets lists the device areas each memory area can be fed from.
+var pollTargets = map[core.Area][]core.Area{
+	core.AreaDiscreteInputs: {core.AreaCoils, core.AreaDiscreteInputs},
+	core.AreaInputRegs:      {core.AreaHoldingRegs, core.AreaInputRegs},
+}
+
+func (c *AppConfig) ValidatePollers() error {
+	// Pollers are optional
+	for name, p := range c.Pollers {
+		path := fmt.Sprintf("pollers.%s", name)
+
+		if _, _, err := net.SplitHostPort(p.Address); err != nil {
+			return fmt.Errorf("%s.address invalid: %q", path, p.Address)
+		}
+
+		if p.IntervalMS <= 0 {
+			return fmt.Errorf("%s.interval_ms must be > 0", path)
+		}
+		if p.TimeoutMS < 0 {
+			return fmt.Errorf("%s.timeout_ms must be >= 0", path)
+		}
+
+		block, ok := c.Memory.Memories[p.Memory]
+		if !ok {
+			return fmt.Errorf("%s references unknown memory '%s'", path, p.Memory)
+		}
+
+		if len(p.Reads) == 0 {
+			return fmt.Errorf("%s.reads cannot be empty", path)
+		}
+
+		for i, r := range p.Reads {
+			if err := r.validate(fmt.Sprintf("%s.reads[%d]", path, i), block); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (r PollRead) validate(path string, block MemoryBlock) error {
+	source, ok := core.ParseArea(r.Area)
+	if !ok {
+		return fmt.Errorf("%s.area invalid: %q", path, r.Area)
+	}
+	target, ok := core.ParseArea(r.Target)
+	if !ok {
+		return fmt.Errorf("%s.target invalid: %q", path, r.Target)
+	}
+
+	if !containsArea(pollTargets[target], source) {
+		return fmt.Errorf("%s: %s cannot be fed from %s", path, target, source)
+	}
+
+	// Spec read limits for one request
+	limit := 125
+	if source == core.AreaCoils || source == core.AreaDiscreteInputs {
+		limit = 2000
+	}
+	if r.Count < 1 || r.Count > limit {
+		return fmt.Errorf("%s.count must be 1..%d", path, limit)
+	}
+	if r.Address < 0 || r.Address+r.Count > 65536 {
+		return fmt.Errorf("%s: device range must lie within 0..65535", path)
+	}
+
+	// The target range must lie within the memory window
+	var area AreaConfig
+	if target == core.AreaDiscreteInputs {
+		area = block.DiscreteInputs
+	} else {
+		area = block.InputRegisters
+	}
+	if r.TargetAddress < area.Start || r.TargetAddress+r.Count > area.Start+area.Size {
+		return fmt.Errorf(
+			"%s: target %d..%d outside %s window %d..%d",
+			path, r.TargetAddress, r.TargetAddress+r.Count-1,
+			target, area.Start, area.Start+area.Size-1,
+		)
+	}
+
+	if target == core.AreaInputRegs && block.coversGeneration(r.TargetAddress, r.Count) {
+		return fmt.Errorf("%s: target overlaps the read-only generation_register", path)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidatePollers(t *testing.T) {
+	base := PollerConfig{
+		Address:    "10.0.0.5:502",
+		UnitID:     1,
+		IntervalMS: 1000,
+		Memory:     "plant_a",
+	}
+
+	cases := []struct {
+		name  string
+		reads []PollRead
+		err   string
+	}{
+		{"registers into input registers", []PollRead{
+			{Area: "holding_registers", Address: 0, Count: 8, Target: "input_registers", TargetAddress: 0},
+		}, ""},
+		{"coils into discrete inputs", []PollRead{
+			{Area: "coils", Count: 8, Target: "discrete_inputs"},
+		}, ""},
+		{"no reads", nil, "reads cannot be empty"},
+		{"writable target", []PollRead{
+			{Area: "holding_registers", Count: 1, Target: "holding_registers"},
+		}, "cannot be fed"},
+		{"bits into registers", []PollRead{
+			{Area: "coils", Count: 1, Target: "input_registers"},
+		}, "cannot be fed"},
+		{"over read limit", []PollRead{
+			{Area: "input_registers", Count: 126, Target: "input_registers"},
+		}, "count must be 1..125"},
+		{"past memory", []PollRead{
+			{Area: "input_registers", Count: 4, Target: "input_registers", TargetAddress: 6},
+		}, "outside input_registers window"},
+	}
+
+	for _, tc := range cases {
+		cfg := routingTestConfig(RoutingStrict)
+		p := base
+		p.Reads = tc.reads
+		cfg.Pollers = Pollers{"meter": p}
+
+		err := cfg.ValidatePollers()
+		switch {
+		case tc.err == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
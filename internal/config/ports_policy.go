@@ -33,13 +33,8 @@ func (p PortPolicy) AllowsMemory(memoryID string) bool {
 // That happens later in the resolver.
 func (p PortPolicy) AllowsFunctionCode(fc uint8) bool {
 	// Step 1: access mode gate
-	if p.Access == AccessReadOnly {
-		switch fc {
-		case 1, 2, 3, 4:
-			// allowed reads
-		default:
-			return false
-		}
+	if p.Access == AccessReadOnly && IsWriteFunctionCode(fc) {
+		return false
 	}
 
 	// Step 2: optional function code overrides
@@ -66,3 +61,30 @@ func (p PortPolicy) AllowsFunctionCode(fc uint8) bool {
 
 	return true
 }
+
+// IsWriteFunctionCode returns true if the function code can mutate memory.
+// Unknown function codes are treated as writes so that read-only ports
+// stay closed by default.
+func IsWriteFunctionCode(fc uint8) bool {
+	switch fc {
+	case 0x01, 0x02, 0x03, 0x04:
+		// pure reads
+		return false
+	case 0x08, 0x0B, 0x11:
+		// Diagnostics, Get Comm Event Counter, Report Server ID
+		return false
+	case 0x2B:
+		// Encapsulated Interface Transport (Read Device Identification only)
+		return false
+	case 0x05, 0x06, 0x0F, 0x10:
+		return true
+	case 0x16:
+		// Mask Write Register is a read-modify-write
+		return true
+	case 0x17:
+		// Read/Write Multiple Registers writes before it reads
+		return true
+	default:
+		return true
+	}
+}
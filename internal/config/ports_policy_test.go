@@ -39,6 +39,9 @@ func TestAllowsFunctionCode_ReadOnly(t *testing.T) {
 	if p.AllowsFunctionCode(6) { // Write Single Register
 		t.Fatal("expected FC06 denied")
 	}
+	if p.AllowsFunctionCode(0x17) { // Read/Write Multiple Registers
+		t.Fatal("expected FC23 denied")
+	}
 }
 
 func TestAllowsFunctionCode_AllowList(t *testing.T) {
@@ -56,3 +59,13 @@ func TestAllowsFunctionCode_AllowList(t *testing.T) {
 		t.Fatal("expected FC04 denied")
 	}
 }
+
+func TestAllowsFunctionCode_ReadOnlyDeniesMaskWrite(t *testing.T) {
+	p := PortPolicy{
+		Access: AccessReadOnly,
+	}
+
+	if p.AllowsFunctionCode(0x16) {
+		t.Fatal("expected FC22 denied")
+	}
+}
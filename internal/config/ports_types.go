@@ -4,14 +4,32 @@ package config
 type Ports map[uint16]PortPolicy
 
 // PortPolicy defines access policy for a TCP port.
-// Policy only — never routing.
+// Policy, plus an optional per-port routing table override.
 type PortPolicy struct {
-	UnitIDs       UnitIDSelector    `yaml:"unit_ids"`
-	Memories      MemorySelector    `yaml:"memories"`
-	Access        AccessMode        `yaml:"access"`
-	FunctionCodes *FunctionCodeACL  `yaml:"function_codes,omitempty"`
-	IPFilter      IPFilterConfig    `yaml:"ip_filter,omitempty"`
-	MaxConnections int               `yaml:"max_connections,omitempty"`
+	UnitIDs        UnitIDSelector   `yaml:"unit_ids"`
+	Memories       MemorySelector   `yaml:"memories"`
+	Access         AccessMode       `yaml:"access"`
+	FunctionCodes  *FunctionCodeACL `yaml:"function_codes,omitempty"`
+	IPFilter       IPFilterConfig   `yaml:"ip_filter,omitempty"`
+	MaxConnections int              `yaml:"max_connections,omitempty"`
+	Framing        FramingMode      `yaml:"framing,omitempty"`
+	Transport      TransportMode    `yaml:"transport,omitempty"`
+	TLS            *PortTLSConfig   `yaml:"tls,omitempty"`
+	AddressACL     *AddressACL      `yaml:"address_acl,omitempty"`
+	Views          []ViewWindow     `yaml:"views,omitempty"`
+	Broadcast      bool             `yaml:"broadcast,omitempty"`
+
+	// Connection timeouts (TCP only), see timeouts.go
+	IdleTimeoutMS    int              `yaml:"idle_timeout_ms,omitempty"`
+	RequestTimeoutMS int              `yaml:"request_timeout_ms,omitempty"`
+	KeepAlive        *KeepAliveConfig `yaml:"keepalive,omitempty"`
+
+	// PipelineDepth handles up to N requests of one connection
+	// concurrently (TCP with mbap framing); 0 or 1 = one at a time.
+	PipelineDepth int `yaml:"pipeline_depth,omitempty"`
+
+	// Routing overrides the top-level routing for this port only.
+	Routing *RoutingConfig `yaml:"routing,omitempty"`
 }
 
 // AccessMode defines read/write capability.
@@ -22,6 +40,25 @@ const (
 	AccessReadWrite AccessMode = "read-write"
 )
 
+// FramingMode defines how Modbus PDUs are framed on the TCP stream.
+// Empty means mbap.
+type FramingMode string
+
+const (
+	FramingMBAP  FramingMode = "mbap"
+	FramingRTU   FramingMode = "rtu"
+	FramingASCII FramingMode = "ascii"
+)
+
+// TransportMode defines the IP transport of a port.
+// Empty means tcp.
+type TransportMode string
+
+const (
+	TransportTCP TransportMode = "tcp"
+	TransportUDP TransportMode = "udp"
+)
+
 type UnitIDSelector struct {
 	All  bool
 	List []uint8
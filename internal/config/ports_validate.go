@@ -13,38 +13,120 @@ func (c *AppConfig) ValidatePorts() error {
 			return fmt.Errorf("invalid port 0")
 		}
 
-		if !p.UnitIDs.All && len(p.UnitIDs.List) == 0 {
-			return fmt.Errorf("ports.%d.unit_ids cannot be empty", port)
+		name := fmt.Sprintf("ports.%d", port)
+
+		if p.Routing != nil {
+			if err := p.Routing.validate(name+".routing", c.Memory); err != nil {
+				return err
+			}
 		}
 
-		if !p.Memories.All && len(p.Memories.List) == 0 {
-			return fmt.Errorf("ports.%d.memories cannot be empty", port)
+		if err := c.validatePolicy(name, p, c.RoutingFor(p)); err != nil {
+			return err
 		}
 
-		if p.Access != AccessReadOnly && p.Access != AccessReadWrite {
-			return fmt.Errorf("ports.%d.access invalid", port)
+		switch p.Framing {
+		case "", FramingMBAP, FramingRTU, FramingASCII:
+		default:
+			return fmt.Errorf("%s.framing invalid: %q", name, p.Framing)
 		}
 
-		// Validate unit IDs exist in routing
-		for _, uid := range p.UnitIDs.List {
-			if _, ok := c.Routing.UnitIDMap[uid]; !ok {
-				return fmt.Errorf(
-					"ports.%d: unit_id %d not in routing.unit_id_map",
-					port, uid,
-				)
+		switch p.Transport {
+		case "", TransportTCP:
+		case TransportUDP:
+			// Datagrams carry MBAP only and hold no connections
+			if p.Framing != "" && p.Framing != FramingMBAP {
+				return fmt.Errorf("%s: udp transport requires mbap framing", name)
+			}
+			if p.MaxConnections != 0 {
+				return fmt.Errorf("%s: max_connections does not apply to udp", name)
+			}
+			if p.hasTimeouts() {
+				return fmt.Errorf("%s: connection timeouts do not apply to udp", name)
+			}
+			if p.PipelineDepth != 0 {
+				return fmt.Errorf("%s: pipeline_depth does not apply to udp", name)
 			}
+		default:
+			return fmt.Errorf("%s.transport invalid: %q", name, p.Transport)
 		}
 
-		// Validate memories exist
-		for _, mem := range p.Memories.List {
-			if _, ok := c.Memory.Memories[mem]; !ok {
-				return fmt.Errorf(
-					"ports.%d: unknown memory %q",
-					port, mem,
-				)
+		if err := validateTimeouts(name, p); err != nil {
+			return err
+		}
+
+		if p.PipelineDepth < 0 || p.PipelineDepth > MaxPipelineDepth {
+			return fmt.Errorf("%s.pipeline_depth must be 0..%d", name, MaxPipelineDepth)
+		}
+		// Only MBAP transaction IDs let clients match pipelined responses
+		if p.PipelineDepth > 1 && p.Framing != "" && p.Framing != FramingMBAP {
+			return fmt.Errorf("%s: pipeline_depth requires mbap framing", name)
+		}
+
+		if p.TLS != nil {
+			if err := c.validateTLS(name, p); err != nil {
+				return err
 			}
 		}
 	}
 
 	return nil
 }
+
+// validatePolicy checks the access policy shared by every Modbus listener
+// against the routing in effect on it.
+// name is the config path used in error messages (e.g. "ports.502").
+func (c *AppConfig) validatePolicy(name string, p PortPolicy, routing *RoutingConfig) error {
+	if !p.UnitIDs.All && len(p.UnitIDs.List) == 0 {
+		return fmt.Errorf("%s.unit_ids cannot be empty", name)
+	}
+
+	if !p.Memories.All && len(p.Memories.List) == 0 {
+		return fmt.Errorf("%s.memories cannot be empty", name)
+	}
+
+	if p.Access != AccessReadOnly && p.Access != AccessReadWrite {
+		return fmt.Errorf("%s.access invalid", name)
+	}
+
+	// Broadcasts are writes only
+	if p.Broadcast && p.Access != AccessReadWrite {
+		return fmt.Errorf("%s: broadcast requires read-write access", name)
+	}
+
+	// Validate unit IDs exist in routing (any unit ID routes in fallback mode)
+	for _, uid := range p.UnitIDs.List {
+		if routing.Forwards(uid) {
+			continue
+		}
+		if _, ok := routing.Route(uid, routing.FallbackMemoryID(c.Memory)); !ok {
+			return fmt.Errorf(
+				"%s: unit_id %d not in routing.unit_id_map",
+				name, uid,
+			)
+		}
+	}
+
+	// Validate memories exist
+	for _, mem := range p.Memories.List {
+		if _, ok := c.Memory.Memories[mem]; !ok {
+			return fmt.Errorf(
+				"%s: unknown memory %q",
+				name, mem,
+			)
+		}
+	}
+
+	if err := c.validateViews(name, p); err != nil {
+		return err
+	}
+
+	// ACL ranges are wire addresses, so they depend on the views
+	if p.AddressACL != nil {
+		if err := c.validateAddressACL(name, p); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
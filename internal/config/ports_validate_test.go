@@ -0,0 +1,37 @@
+package config
+
+import "testing"
+
+func TestValidatePorts_PipelineDepth(t *testing.T) {
+	cfg := routingTestConfig(RoutingDefaultFallback)
+	port := func(depth int, framing FramingMode, transport TransportMode) Ports {
+		return Ports{502: {
+			UnitIDs:       UnitIDSelector{All: true},
+			Memories:      MemorySelector{All: true},
+			Access:        AccessReadOnly,
+			Framing:       framing,
+			Transport:     transport,
+			PipelineDepth: depth,
+		}}
+	}
+
+	cfg.Ports = port(8, FramingMBAP, "")
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected mbap pipelining valid, got %v", err)
+	}
+
+	cases := map[string]Ports{
+		"negative":  port(-1, "", ""),
+		"too deep":  port(MaxPipelineDepth+1, "", ""),
+		"rtu":       port(8, FramingRTU, ""),
+		"udp":       port(8, "", TransportUDP),
+		"udp depth": port(1, "", TransportUDP),
+	}
+
+	for name, ports := range cases {
+		cfg.Ports = ports
+		if err := cfg.ValidatePorts(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
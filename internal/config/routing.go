@@ -2,23 +2,105 @@ package config
 
 import "fmt"
 
+// RoutingMode selects what happens to unit IDs missing from unit_id_map.
+type RoutingMode string
+
+const (
+	// RoutingStrict rejects unmapped unit IDs (default).
+	RoutingStrict RoutingMode = "strict"
+
+	// RoutingDefaultFallback routes unmapped unit IDs, ingest commands
+	// without a memory and unknown Raw Ingest memory IDs to the
+	// `default: true` memory. Port policy still applies.
+	RoutingDefaultFallback RoutingMode = "default_fallback"
+)
+
+// RoutingConfig maps unit IDs to memories or, in gateway mode, to
+// upstream devices. The top-level routing applies to every listener
+// unless a port declares its own `routing` block.
 type RoutingConfig struct {
-	UnitIDMap map[uint8]string `yaml:"unit_id_map"`
+	Mode      RoutingMode `yaml:"mode,omitempty"`
+	UnitIDMap UnitIDMap   `yaml:"unit_id_map"`
+	Upstreams UpstreamMap `yaml:"upstreams,omitempty"`
 }
 
 func (r *RoutingConfig) Validate(mem MemoryConfig) error {
-	if len(r.UnitIDMap) == 0 {
-		return fmt.Errorf("unit_id_map must not be empty (strict mapping enabled)")
-	}
+	return r.validate("routing", mem)
+}
 
-	for unitID, memID := range r.UnitIDMap {
-		if _, ok := mem.Memories[memID]; !ok {
-			return fmt.Errorf(
-				"unit_id_map[%d] references unknown memory '%s'",
-				unitID, memID,
-			)
+// validate checks one routing table; name is the config path used in
+// error messages (e.g. "ports.1502.routing").
+func (r *RoutingConfig) validate(name string, mem MemoryConfig) error {
+	switch r.Mode {
+	case "", RoutingStrict:
+		if len(r.UnitIDMap) == 0 && len(r.Upstreams) == 0 {
+			return fmt.Errorf("%s.unit_id_map must not be empty (strict mapping enabled)", name)
 		}
+	case RoutingDefaultFallback:
+		// An empty map is valid: everything lands on the default memory
+	default:
+		return fmt.Errorf("%s.mode invalid: %q", name, r.Mode)
 	}
 
-	return nil
+	if err := r.UnitIDMap.validate(name+".unit_id_map", mem); err != nil {
+		return err
+	}
+	return r.Upstreams.validate(name+".upstreams", r.UnitIDMap)
+}
+
+// Fallback reports whether unmapped requests fall through to the
+// default memory.
+func (r *RoutingConfig) Fallback() bool {
+	return r.Mode == RoutingDefaultFallback
+}
+
+// Route returns the memory ID for a unit ID. fallback is the memory used
+// for unmapped unit IDs; pass "" for strict routing. Unit IDs forwarded
+// to an upstream device have no memory.
+func (r *RoutingConfig) Route(unitID uint8, fallback string) (string, bool) {
+	if memID, ok := r.UnitIDMap.Lookup(unitID); ok {
+		return memID, true
+	}
+	if r.Forwards(unitID) {
+		return "", false
+	}
+	if fallback != "" {
+		return fallback, true
+	}
+	return "", false
+}
+
+// Forwards reports whether a unit ID is served by an upstream device.
+func (r *RoutingConfig) Forwards(unitID uint8) bool {
+	_, ok := r.Upstreams.Lookup(unitID)
+	return ok
+}
+
+// FallbackMemoryID returns the default memory when r allows fallback,
+// or "" in strict mode.
+func (r *RoutingConfig) FallbackMemoryID(mem MemoryConfig) string {
+	if !r.Fallback() {
+		return ""
+	}
+
+	id, err := mem.DefaultMemoryID()
+	if err != nil {
+		// Memory validation guarantees a default memory
+		return ""
+	}
+	return id
+}
+
+// FallbackMemoryID returns the default memory for the top-level routing.
+// Used by REST/MQTT ingest and Raw Ingest, which have no port.
+func (c *AppConfig) FallbackMemoryID() string {
+	return c.Routing.FallbackMemoryID(c.Memory)
+}
+
+// RoutingFor returns the routing table in effect on a port.
+func (c *AppConfig) RoutingFor(p PortPolicy) *RoutingConfig {
+	if p.Routing != nil {
+		return p.Routing
+	}
+	return &c.Routing
 }
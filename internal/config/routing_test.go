@@ -0,0 +1,78 @@
+package config
+
+import "testing"
+
+func routingTestConfig(mode RoutingMode) *AppConfig {
+	area := AreaConfig{Size: 8}
+	return &AppConfig{
+		Memory: MemoryConfig{Memories: map[string]MemoryBlock{
+			"plant_a": {Default: true, Coils: area, DiscreteInputs: area, HoldingRegisters: area, InputRegisters: area},
+			"plant_b": {Coils: area, DiscreteInputs: area, HoldingRegisters: area, InputRegisters: area},
+		}},
+		Routing: RoutingConfig{
+			Mode:      mode,
+			UnitIDMap: UnitIDMap{{First: 2, Last: 2, Memory: "plant_b"}},
+		},
+	}
+}
+
+func TestRoute_Strict(t *testing.T) {
+	cfg := routingTestConfig(RoutingStrict)
+	fallback := cfg.FallbackMemoryID()
+
+	if fallback != "" {
+		t.Fatalf("expected no fallback in strict mode, got %q", fallback)
+	}
+	if _, ok := cfg.Routing.Route(1, fallback); ok {
+		t.Fatal("expected unmapped unit 1 rejected")
+	}
+}
+
+func TestRoute_DefaultFallback(t *testing.T) {
+	cfg := routingTestConfig(RoutingDefaultFallback)
+	fallback := cfg.FallbackMemoryID()
+
+	if memID, ok := cfg.Routing.Route(1, fallback); !ok || memID != "plant_a" {
+		t.Fatalf("expected unit 1 → plant_a, got %q %v", memID, ok)
+	}
+	// Explicit mappings win
+	if memID, _ := cfg.Routing.Route(2, fallback); memID != "plant_b" {
+		t.Fatalf("expected unit 2 → plant_b, got %q", memID)
+	}
+}
+
+func TestRoutingValidate_Modes(t *testing.T) {
+	cfg := routingTestConfig(RoutingDefaultFallback)
+	cfg.Routing.UnitIDMap = nil
+	if err := cfg.Routing.Validate(cfg.Memory); err != nil {
+		t.Fatalf("expected empty map valid with fallback, got %v", err)
+	}
+
+	cfg.Routing.Mode = RoutingStrict
+	if err := cfg.Routing.Validate(cfg.Memory); err == nil {
+		t.Fatal("expected empty map rejected in strict mode")
+	}
+
+	cfg.Routing.Mode = "loose"
+	if err := cfg.Routing.Validate(cfg.Memory); err == nil {
+		t.Fatal("expected unknown mode rejected")
+	}
+}
+
+func TestValidatePorts_FallbackUnitIDs(t *testing.T) {
+	cfg := routingTestConfig(RoutingDefaultFallback)
+	cfg.Ports = Ports{502: {
+		UnitIDs:  UnitIDSelector{List: []uint8{1, 17}},
+		Memories: MemorySelector{List: []string{"plant_a"}},
+		Access:   AccessReadOnly,
+	}}
+
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected unmapped unit IDs valid with fallback, got %v", err)
+	}
+
+	cfg.Routing.Mode = RoutingStrict
+	if err := cfg.ValidatePorts(); err == nil {
+		t.Fatal("expected unmapped unit IDs rejected in strict mode")
+	}
+}
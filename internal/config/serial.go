@@ -0,0 +1,122 @@
+package config
+
+import "fmt"
+
+// SerialPorts is a map keyed by serial device path (e.g. /dev/ttyUSB0).
+// Each entry runs MMA as a Modbus slave on that line.
+type SerialPorts map[string]SerialPortPolicy
+
+// SerialPortPolicy defines line settings and access policy for one
+// serial device. The access policy fields are the same as for TCP ports;
+// ip_filter, max_connections, connection timeouts and pipeline_depth do
+// not apply to serial lines.
+type SerialPortPolicy struct {
+	BaudRate int          `yaml:"baud_rate"`
+	DataBits int          `yaml:"data_bits,omitempty"` // default 8
+	Parity   SerialParity `yaml:"parity,omitempty"`    // default even
+	StopBits int          `yaml:"stop_bits,omitempty"` // default 1
+
+	// SilentIntervalUS is the t3.5 inter-frame silence in microseconds.
+	// 0 derives it from the baud rate.
+	SilentIntervalUS int `yaml:"silent_interval_us,omitempty"`
+
+	PortPolicy `yaml:",inline"`
+}
+
+// SerialParity defines the serial parity bit.
+type SerialParity string
+
+const (
+	ParityNone SerialParity = "none"
+	ParityEven SerialParity = "even"
+	ParityOdd  SerialParity = "odd"
+)
+
+// SupportedBaudRates lists the baud rates accepted for serial lines.
+var SupportedBaudRates = []int{1200, 2400, 4800, 9600, 19200, 38400, 57600, 115200, 230400}
+
+func (c *AppConfig) ValidateSerial() error {
+	// Serial lines are optional
+	if c.Serial == nil {
+		return nil
+	}
+
+	for device, s := range c.Serial {
+		if device == "" {
+			return fmt.Errorf("serial device path cannot be empty")
+		}
+
+		name := fmt.Sprintf("serial.%s", device)
+
+		if !isSupportedBaudRate(s.BaudRate) {
+			return fmt.Errorf("%s.baud_rate %d not supported", name, s.BaudRate)
+		}
+
+		switch s.DataBits {
+		case 0, 8:
+		case 7:
+			if s.Framing != FramingASCII {
+				return fmt.Errorf("%s.data_bits 7 requires ascii framing", name)
+			}
+		default:
+			return fmt.Errorf("%s.data_bits must be 7 or 8", name)
+		}
+
+		switch s.Parity {
+		case "", ParityNone, ParityEven, ParityOdd:
+		default:
+			return fmt.Errorf("%s.parity invalid: %q", name, s.Parity)
+		}
+
+		switch s.StopBits {
+		case 0, 1, 2:
+		default:
+			return fmt.Errorf("%s.stop_bits must be 1 or 2", name)
+		}
+
+		if s.SilentIntervalUS < 0 {
+			return fmt.Errorf("%s.silent_interval_us must be >= 0", name)
+		}
+
+		switch s.Framing {
+		case "", FramingRTU, FramingASCII:
+		default:
+			return fmt.Errorf("%s.framing must be rtu or ascii", name)
+		}
+
+		if s.Transport != "" || s.TLS != nil {
+			return fmt.Errorf("%s: transport and tls do not apply to serial lines", name)
+		}
+		if s.hasTimeouts() || s.PipelineDepth != 0 {
+			return fmt.Errorf("%s: connection timeouts and pipeline_depth do not apply to serial lines", name)
+		}
+
+		// With fallback routing every address is routed; a multi-drop
+		// line must still name the slave addresses it owns.
+		routing := c.RoutingFor(s.PortPolicy)
+		if s.Routing != nil {
+			if err := s.Routing.validate(name+".routing", c.Memory); err != nil {
+				return err
+			}
+		}
+
+		if routing.Fallback() && s.UnitIDs.All {
+			return fmt.Errorf("%s.unit_ids must be a list when routing.mode is %s", name, RoutingDefaultFallback)
+		}
+
+		if err := c.validatePolicy(name, s.PortPolicy, routing); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func isSupportedBaudRate(baud int) bool {
+	for _, b := range SupportedBaudRates {
+		if b == baud {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestSerialPorts_InlinePolicy(t *testing.T) {
+	src := `
+serial:
+  /dev/ttyUSB0:
+    baud_rate: 19200
+    parity: none
+    unit_ids: [1, 2]
+    memories: all
+    access: read-only
+`
+	var cfg AppConfig
+	if err := yaml.Unmarshal([]byte(src), &cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := cfg.Serial["/dev/ttyUSB0"]
+	if !ok {
+		t.Fatal("expected /dev/ttyUSB0 entry")
+	}
+	if s.BaudRate != 19200 || s.Parity != ParityNone {
+		t.Fatalf("unexpected line settings: %+v", s)
+	}
+	if !s.AllowsUnitID(2) || s.AllowsUnitID(3) {
+		t.Fatal("expected inline unit_ids policy")
+	}
+	if s.Access != AccessReadOnly {
+		t.Fatalf("expected read-only access, got %q", s.Access)
+	}
+}
+
+func TestValidateSerial(t *testing.T) {
+	base := func() SerialPortPolicy {
+		return SerialPortPolicy{
+			BaudRate: 9600,
+			PortPolicy: PortPolicy{
+				UnitIDs:  UnitIDSelector{All: true},
+				Memories: MemorySelector{All: true},
+				Access:   AccessReadWrite,
+			},
+		}
+	}
+
+	cases := map[string]func(*SerialPortPolicy){
+		"baud rate":        func(s *SerialPortPolicy) { s.BaudRate = 12345 },
+		"7 data bits rtu":  func(s *SerialPortPolicy) { s.DataBits = 7 },
+		"parity":           func(s *SerialPortPolicy) { s.Parity = "mark" },
+		"stop bits":        func(s *SerialPortPolicy) { s.StopBits = 3 },
+		"mbap framing":     func(s *SerialPortPolicy) { s.Framing = FramingMBAP },
+		"negative silence": func(s *SerialPortPolicy) { s.SilentIntervalUS = -1 },
+		"idle timeout":     func(s *SerialPortPolicy) { s.IdleTimeoutMS = 1000 },
+	}
+
+	ok := AppConfig{Serial: SerialPorts{"/dev/ttyS0": base()}}
+	if err := ok.ValidateSerial(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	for name, mutate := range cases {
+		s := base()
+		mutate(&s)
+		cfg := AppConfig{Serial: SerialPorts{"/dev/ttyS0": s}}
+		if err := cfg.ValidateSerial(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
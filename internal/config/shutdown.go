@@ -0,0 +1,21 @@
+package config
+
+import "time"
+
+// DefaultDrainTimeoutMS bounds a graceful shutdown when not configured.
+const DefaultDrainTimeoutMS = 5000
+
+// ShutdownConfig controls graceful shutdown on SIGINT/SIGTERM.
+type ShutdownConfig struct {
+	// DrainTimeoutMS is how long active connections may take to finish
+	// their request in flight before they are cut.
+	DrainTimeoutMS int `yaml:"drain_timeout_ms,omitempty"`
+}
+
+// DrainTimeout returns the drain deadline.
+func (s ShutdownConfig) DrainTimeout() time.Duration {
+	if s.DrainTimeoutMS <= 0 {
+		return DefaultDrainTimeoutMS * time.Millisecond
+	}
+	return time.Duration(s.DrainTimeoutMS) * time.Millisecond
+}
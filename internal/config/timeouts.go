@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// KeepAliveConfig tunes TCP keepalive probes on a port's connections.
+// Zero fields keep the Go defaults (15s idle, 15s interval, 9 probes).
+type KeepAliveConfig struct {
+	Disabled   bool `yaml:"disabled,omitempty"`
+	IdleMS     int  `yaml:"idle_ms,omitempty"`
+	IntervalMS int  `yaml:"interval_ms,omitempty"`
+	Count      int  `yaml:"count,omitempty"`
+}
+
+// IdleTimeout is how long a connection may wait for its next request
+// before it is closed. 0 means no limit.
+func (p PortPolicy) IdleTimeout() time.Duration {
+	return time.Duration(p.IdleTimeoutMS) * time.Millisecond
+}
+
+// RequestTimeout bounds reading a started request and writing its
+// response. 0 means no limit.
+func (p PortPolicy) RequestTimeout() time.Duration {
+	return time.Duration(p.RequestTimeoutMS) * time.Millisecond
+}
+
+// hasTimeouts reports whether any connection timeout setting is present.
+func (p PortPolicy) hasTimeouts() bool {
+	return p.IdleTimeoutMS != 0 || p.RequestTimeoutMS != 0 || p.KeepAlive != nil
+}
+
+// validateTimeouts checks the connection timeouts of a TCP port.
+func validateTimeouts(name string, p PortPolicy) error {
+	if p.IdleTimeoutMS < 0 {
+		return fmt.Errorf("%s.idle_timeout_ms must be >= 0", name)
+	}
+	if p.RequestTimeoutMS < 0 {
+		return fmt.Errorf("%s.request_timeout_ms must be >= 0", name)
+	}
+
+	if k := p.KeepAlive; k != nil {
+		if k.IdleMS < 0 || k.IntervalMS < 0 || k.Count < 0 {
+			return fmt.Errorf("%s.keepalive values must be >= 0", name)
+		}
+		if k.Disabled && (k.IdleMS != 0 || k.IntervalMS != 0 || k.Count != 0) {
+			return fmt.Errorf("%s.keepalive: disabled keepalive takes no settings", name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import "testing"
+
+func TestValidatePorts_Timeouts(t *testing.T) {
+	base := func() PortPolicy {
+		return PortPolicy{
+			UnitIDs:  UnitIDSelector{All: true},
+			Memories: MemorySelector{All: true},
+			Access:   AccessReadOnly,
+		}
+	}
+
+	cfg := routingTestConfig(RoutingDefaultFallback)
+
+	p := base()
+	p.IdleTimeoutMS = 30000
+	p.RequestTimeoutMS = 2000
+	p.KeepAlive = &KeepAliveConfig{IdleMS: 10000, Count: 3}
+	cfg.Ports = Ports{502: p}
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected valid timeouts, got %v", err)
+	}
+
+	cases := map[string]func(*PortPolicy){
+		"negative idle":    func(p *PortPolicy) { p.IdleTimeoutMS = -1 },
+		"negative request": func(p *PortPolicy) { p.RequestTimeoutMS = -1 },
+		"negative probes":  func(p *PortPolicy) { p.KeepAlive = &KeepAliveConfig{Count: -1} },
+		"disabled with settings": func(p *PortPolicy) {
+			p.KeepAlive = &KeepAliveConfig{Disabled: true, IdleMS: 1000}
+		},
+		"udp": func(p *PortPolicy) {
+			p.Transport = TransportUDP
+			p.IdleTimeoutMS = 1000
+		},
+	}
+
+	for name, mutate := range cases {
+		p := base()
+		mutate(&p)
+		cfg.Ports = Ports{502: p}
+		if err := cfg.ValidatePorts(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package config
+
+import "fmt"
+
+// PortTLSConfig enables Modbus/TCP Security (mutual TLS) on a port.
+// Clients must present a certificate signed by the client CA.
+type PortTLSConfig struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
+
+	// Roles maps the role carried in the client certificate
+	// (OID 1.3.6.1.4.1.50316.802.1) to the access it grants.
+	// Clients without a listed role are rejected.
+	Roles map[string]RolePolicy `yaml:"roles"`
+}
+
+// RolePolicy narrows the port policy for clients holding one role.
+// A request must be allowed by both the port and the role.
+type RolePolicy struct {
+	UnitIDs       UnitIDSelector   `yaml:"unit_ids"`
+	Memories      MemorySelector   `yaml:"memories"`
+	Access        AccessMode       `yaml:"access"`
+	FunctionCodes *FunctionCodeACL `yaml:"function_codes,omitempty"`
+}
+
+// Policy returns the role as a PortPolicy so the same Allows* checks apply.
+func (r RolePolicy) Policy() PortPolicy {
+	return PortPolicy{
+		UnitIDs:       r.UnitIDs,
+		Memories:      r.Memories,
+		Access:        r.Access,
+		FunctionCodes: r.FunctionCodes,
+	}
+}
+
+func (c *AppConfig) validateTLS(name string, p PortPolicy) error {
+	t := p.TLS
+
+	if t.CertFile == "" || t.KeyFile == "" {
+		return fmt.Errorf("%s.tls: cert_file and key_file are required", name)
+	}
+	if t.ClientCAFile == "" {
+		return fmt.Errorf("%s.tls: client_ca_file is required (mutual auth)", name)
+	}
+
+	if p.Transport == TransportUDP {
+		return fmt.Errorf("%s.tls requires tcp transport", name)
+	}
+	if p.Framing != "" && p.Framing != FramingMBAP {
+		return fmt.Errorf("%s.tls requires mbap framing", name)
+	}
+
+	if len(t.Roles) == 0 {
+		return fmt.Errorf("%s.tls.roles cannot be empty", name)
+	}
+
+	for role, r := range t.Roles {
+		if role == "" {
+			return fmt.Errorf("%s.tls.roles: role name cannot be empty", name)
+		}
+
+		if err := c.validatePolicy(
+			fmt.Sprintf("%s.tls.roles.%s", name, role),
+			r.Policy(),
+			c.RoutingFor(p),
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
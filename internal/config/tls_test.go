@@ -0,0 +1,72 @@
+package config
+
+import "testing"
+
+func tlsTestConfig() *AppConfig {
+	return &AppConfig{
+		Memory:  MemoryConfig{Memories: map[string]MemoryBlock{"plant_a": {}}},
+		Routing: RoutingConfig{UnitIDMap: UnitIDMap{{First: 1, Last: 1, Memory: "plant_a"}}},
+		Ports: Ports{
+			802: {
+				UnitIDs:  UnitIDSelector{All: true},
+				Memories: MemorySelector{All: true},
+				Access:   AccessReadWrite,
+				TLS: &PortTLSConfig{
+					CertFile:     "server.crt",
+					KeyFile:      "server.key",
+					ClientCAFile: "clients-ca.crt",
+					Roles: map[string]RolePolicy{
+						"operator": {
+							UnitIDs:  UnitIDSelector{List: []uint8{1}},
+							Memories: MemorySelector{All: true},
+							Access:   AccessReadOnly,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidatePorts_TLS(t *testing.T) {
+	if err := tlsTestConfig().ValidatePorts(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	cases := map[string]func(p *PortPolicy){
+		"no client CA": func(p *PortPolicy) { p.TLS.ClientCAFile = "" },
+		"no roles":     func(p *PortPolicy) { p.TLS.Roles = nil },
+		"udp":          func(p *PortPolicy) { p.Transport = TransportUDP },
+		"rtu framing":  func(p *PortPolicy) { p.Framing = FramingRTU },
+		"unrouted unit": func(p *PortPolicy) {
+			p.TLS.Roles["operator"] = RolePolicy{UnitIDs: UnitIDSelector{List: []uint8{9}}, Memories: MemorySelector{All: true}, Access: AccessReadOnly}
+		},
+	}
+
+	for name, mutate := range cases {
+		cfg := tlsTestConfig()
+		p := cfg.Ports[802]
+		mutate(&p)
+		cfg.Ports[802] = p
+
+		if err := cfg.ValidatePorts(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestRolePolicy_Policy(t *testing.T) {
+	r := RolePolicy{
+		UnitIDs:  UnitIDSelector{List: []uint8{1}},
+		Memories: MemorySelector{All: true},
+		Access:   AccessReadOnly,
+	}
+
+	p := r.Policy()
+	if !p.AllowsUnitID(1) || p.AllowsUnitID(2) {
+		t.Fatal("expected role unit_ids carried over")
+	}
+	if p.AllowsFunctionCode(0x06) {
+		t.Fatal("expected read-only role to deny FC06")
+	}
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnitIDMap routes unit IDs to memories, in declaration order.
+// YAML keys are single IDs (`1: plant_a`) or inclusive ranges
+// (`10-19: plant_b`).
+type UnitIDMap []UnitIDRoute
+
+// UnitIDRoute maps the unit IDs First..Last (inclusive) to one memory.
+type UnitIDRoute struct {
+	First  uint8
+	Last   uint8
+	Memory string
+}
+
+func (r UnitIDRoute) contains(unitID uint8) bool {
+	return unitID >= r.First && unitID <= r.Last
+}
+
+func (r UnitIDRoute) String() string {
+	if r.First == r.Last {
+		return strconv.Itoa(int(r.First))
+	}
+	return fmt.Sprintf("%d-%d", r.First, r.Last)
+}
+
+// Lookup returns the memory routed for unitID.
+func (m UnitIDMap) Lookup(unitID uint8) (string, bool) {
+	for _, r := range m {
+		if r.contains(unitID) {
+			return r.Memory, true
+		}
+	}
+	return "", false
+}
+
+// validate rejects overlapping entries and unknown memories.
+// name is the config path used in error messages.
+func (m UnitIDMap) validate(name string, mem MemoryConfig) error {
+	for i, r := range m {
+		if _, ok := mem.Memories[r.Memory]; !ok {
+			return fmt.Errorf(
+				"%s[%s] references unknown memory '%s'",
+				name, r, r.Memory,
+			)
+		}
+
+		for _, prev := range m[:i] {
+			if r.First <= prev.Last && prev.First <= r.Last {
+				return fmt.Errorf(
+					"%s: %s overlaps %s",
+					name, r, prev,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *UnitIDMap) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("unit_id_map must be a mapping")
+	}
+
+	out := make(UnitIDMap, 0, len(n.Content)/2)
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		first, last, err := parseUnitIDKey(n.Content[i].Value)
+		if err != nil {
+			return err
+		}
+
+		var memID string
+		if err := n.Content[i+1].Decode(&memID); err != nil {
+			return err
+		}
+
+		out = append(out, UnitIDRoute{First: first, Last: last, Memory: memID})
+	}
+
+	*m = out
+	return nil
+}
+
+func (m UnitIDMap) MarshalYAML() (any, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, r := range m {
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: r.String()},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: r.Memory},
+		)
+	}
+	return n, nil
+}
+
+// parseUnitIDKey parses "7" or "10-19".
+func parseUnitIDKey(key string) (uint8, uint8, error) {
+	lo, hi, isRange := strings.Cut(key, "-")
+
+	first, err := parseUnitID(lo)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unit_id_map key %q: %w", key, err)
+	}
+	if !isRange {
+		return first, first, nil
+	}
+
+	last, err := parseUnitID(hi)
+	if err != nil {
+		return 0, 0, fmt.Errorf("unit_id_map key %q: %w", key, err)
+	}
+	if last < first {
+		return 0, 0, fmt.Errorf("unit_id_map key %q: range end before start", key)
+	}
+	return first, last, nil
+}
+
+func parseUnitID(s string) (uint8, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || v < 0 || v > 255 {
+		return 0, fmt.Errorf("unit_id must be 0..255")
+	}
+	return uint8(v), nil
+}
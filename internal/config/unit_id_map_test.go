@@ -0,0 +1,126 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestUnitIDMap_UnmarshalRanges(t *testing.T) {
+	src := `
+unit_id_map:
+  1: plant_a
+  10-19: plant_b
+`
+	var r RoutingConfig
+	if err := yaml.Unmarshal([]byte(src), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[uint8]string{1: "plant_a", 10: "plant_b", 15: "plant_b", 19: "plant_b"}
+	for uid, want := range cases {
+		if got, ok := r.UnitIDMap.Lookup(uid); !ok || got != want {
+			t.Fatalf("unit %d: expected %q, got %q (%v)", uid, want, got, ok)
+		}
+	}
+	for _, uid := range []uint8{0, 2, 9, 20} {
+		if _, ok := r.UnitIDMap.Lookup(uid); ok {
+			t.Fatalf("unit %d: expected unmapped", uid)
+		}
+	}
+}
+
+func TestUnitIDMap_RoundTrip(t *testing.T) {
+	in := RoutingConfig{UnitIDMap: UnitIDMap{
+		{First: 1, Last: 1, Memory: "plant_a"},
+		{First: 10, Last: 19, Memory: "plant_b"},
+	}}
+
+	out, err := yaml.Marshal(&in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back RoutingConfig
+	if err := yaml.Unmarshal(out, &back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back.UnitIDMap) != 2 || back.UnitIDMap[1] != in.UnitIDMap[1] {
+		t.Fatalf("round trip mismatch: %+v", back.UnitIDMap)
+	}
+}
+
+func TestUnitIDMap_InvalidKeys(t *testing.T) {
+	for _, key := range []string{"256", "19-10", "a-b", "-1", "5-300"} {
+		var r RoutingConfig
+		src := "unit_id_map:\n  \"" + key + "\": plant_a\n"
+		if err := yaml.Unmarshal([]byte(src), &r); err == nil {
+			t.Fatalf("key %q: expected parse error", key)
+		}
+	}
+}
+
+func TestRoutingValidate_Overlaps(t *testing.T) {
+	mem := routingTestConfig(RoutingStrict).Memory
+
+	cases := map[string]UnitIDMap{
+		"range over single": {
+			{First: 12, Last: 12, Memory: "plant_a"},
+			{First: 10, Last: 19, Memory: "plant_b"},
+		},
+		"ranges": {
+			{First: 10, Last: 19, Memory: "plant_a"},
+			{First: 19, Last: 30, Memory: "plant_b"},
+		},
+		"unknown memory": {
+			{First: 10, Last: 19, Memory: "plant_x"},
+		},
+	}
+
+	for name, m := range cases {
+		r := RoutingConfig{UnitIDMap: m}
+		if err := r.Validate(mem); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	ok := RoutingConfig{UnitIDMap: UnitIDMap{
+		{First: 10, Last: 19, Memory: "plant_a"},
+		{First: 20, Last: 29, Memory: "plant_b"},
+	}}
+	if err := ok.Validate(mem); err != nil {
+		t.Fatalf("expected adjacent ranges valid, got %v", err)
+	}
+}
+
+func TestValidatePorts_PerPortRouting(t *testing.T) {
+	cfg := routingTestConfig(RoutingStrict)
+	cfg.Ports = Ports{
+		1502: {
+			UnitIDs:  UnitIDSelector{List: []uint8{1}},
+			Memories: MemorySelector{All: true},
+			Access:   AccessReadOnly,
+			Routing: &RoutingConfig{UnitIDMap: UnitIDMap{
+				{First: 1, Last: 1, Memory: "plant_b"},
+			}},
+		},
+	}
+
+	// Unit 1 is only routed on port 1502
+	if err := cfg.ValidatePorts(); err != nil {
+		t.Fatalf("expected per-port routing valid, got %v", err)
+	}
+	if memID, _ := cfg.RoutingFor(cfg.Ports[1502]).Route(1, ""); memID != "plant_b" {
+		t.Fatalf("expected port 1502 unit 1 → plant_b, got %q", memID)
+	}
+	if _, ok := cfg.Routing.Route(1, ""); ok {
+		t.Fatal("expected unit 1 unmapped in global routing")
+	}
+
+	// Overlaps inside a port table are rejected by ValidatePorts
+	p := cfg.Ports[1502]
+	p.Routing.UnitIDMap = append(p.Routing.UnitIDMap, UnitIDRoute{First: 0, Last: 5, Memory: "plant_a"})
+	if err := cfg.ValidatePorts(); err == nil {
+		t.Fatal("expected overlapping port routing rejected")
+	}
+}
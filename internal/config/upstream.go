@@ -0,0 +1,152 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults for upstream devices (gateway mode).
+const (
+	DefaultUpstreamTimeoutMS      = 1000
+	DefaultUpstreamMaxConnections = 1
+)
+
+// UpstreamMap routes unit IDs to remote Modbus TCP devices instead of
+// memories (gateway mode). YAML keys are single IDs or inclusive ranges,
+// as in unit_id_map.
+type UpstreamMap []UpstreamRoute
+
+// UpstreamRoute forwards the unit IDs First..Last to one device.
+type UpstreamRoute struct {
+	First uint8
+	Last  uint8
+	Upstream
+}
+
+// Upstream is a remote Modbus TCP device. Each entry gets its own
+// connection pool.
+type Upstream struct {
+	Address string `yaml:"address"` // host:port
+
+	// UnitID is the unit ID sent upstream; default: the requested one.
+	UnitID *uint8 `yaml:"unit_id,omitempty"`
+
+	TimeoutMS      int `yaml:"timeout_ms,omitempty"`
+	MaxConnections int `yaml:"max_connections,omitempty"`
+}
+
+func (r UpstreamRoute) contains(unitID uint8) bool {
+	return unitID >= r.First && unitID <= r.Last
+}
+
+func (r UpstreamRoute) String() string {
+	return UnitIDRoute{First: r.First, Last: r.Last}.String()
+}
+
+// RemoteUnitID returns the unit ID to send upstream for a request.
+func (u Upstream) RemoteUnitID(unitID uint8) uint8 {
+	if u.UnitID != nil {
+		return *u.UnitID
+	}
+	return unitID
+}
+
+// Timeout bounds one forwarded request, connection setup included.
+func (u Upstream) Timeout() time.Duration {
+	if u.TimeoutMS <= 0 {
+		return DefaultUpstreamTimeoutMS * time.Millisecond
+	}
+	return time.Duration(u.TimeoutMS) * time.Millisecond
+}
+
+// PoolSize returns the connection limit towards the device.
+func (u Upstream) PoolSize() int {
+	if u.MaxConnections <= 0 {
+		return DefaultUpstreamMaxConnections
+	}
+	return u.MaxConnections
+}
+
+// Lookup returns the index of the route serving unitID.
+func (m UpstreamMap) Lookup(unitID uint8) (int, bool) {
+	for i, r := range m {
+		if r.contains(unitID) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// validate rejects bad addresses and unit IDs that are routed twice.
+// A unit ID is served either by a memory or by an upstream device.
+func (m UpstreamMap) validate(name string, local UnitIDMap) error {
+	for i, r := range m {
+		path := fmt.Sprintf("%s[%s]", name, r)
+
+		if _, _, err := net.SplitHostPort(r.Address); err != nil {
+			return fmt.Errorf("%s.address invalid: %q", path, r.Address)
+		}
+		if r.TimeoutMS < 0 {
+			return fmt.Errorf("%s.timeout_ms must be >= 0", path)
+		}
+		if r.MaxConnections < 0 {
+			return fmt.Errorf("%s.max_connections must be >= 0", path)
+		}
+
+		for _, prev := range m[:i] {
+			if r.First <= prev.Last && prev.First <= r.Last {
+				return fmt.Errorf("%s: %s overlaps %s", name, r, prev)
+			}
+		}
+		for _, l := range local {
+			if r.First <= l.Last && l.First <= r.Last {
+				return fmt.Errorf("%s: %s overlaps unit_id_map[%s]", name, r, l)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *UpstreamMap) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("upstreams must be a mapping")
+	}
+
+	out := make(UpstreamMap, 0, len(n.Content)/2)
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		first, last, err := parseUnitIDKey(n.Content[i].Value)
+		if err != nil {
+			return err
+		}
+
+		var u Upstream
+		if err := n.Content[i+1].Decode(&u); err != nil {
+			return err
+		}
+
+		out = append(out, UpstreamRoute{First: first, Last: last, Upstream: u})
+	}
+
+	*m = out
+	return nil
+}
+
+func (m UpstreamMap) MarshalYAML() (any, error) {
+	n := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, r := range m {
+		var v yaml.Node
+		if err := v.Encode(r.Upstream); err != nil {
+			return nil, err
+		}
+		n.Content = append(n.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: r.String()},
+			&v,
+		)
+	}
+	return n, nil
+}
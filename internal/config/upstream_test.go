@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestUpstreamMap_Unmarshal(t *testing.T) {
+	src := `
+unit_id_map:
+  1: plant_a
+upstreams:
+  10-19:
+    address: 192.168.1.50:502
+  20:
+    address: plc.local:502
+    unit_id: 1
+    max_connections: 4
+`
+	var r RoutingConfig
+	if err := yaml.Unmarshal([]byte(src), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.Upstreams) != 2 {
+		t.Fatalf("expected 2 upstreams, got %+v", r.Upstreams)
+	}
+
+	i, ok := r.Upstreams.Lookup(15)
+	if !ok || r.Upstreams[i].RemoteUnitID(15) != 15 {
+		t.Fatalf("expected unit 15 forwarded unchanged, got %d %v", i, ok)
+	}
+	i, ok = r.Upstreams.Lookup(20)
+	if !ok || r.Upstreams[i].RemoteUnitID(20) != 1 || r.Upstreams[i].PoolSize() != 4 {
+		t.Fatalf("unexpected route for unit 20: %+v", r.Upstreams[i])
+	}
+
+	// Forwarded unit IDs have no memory, even with fallback
+	if _, routed := r.Route(15, "plant_a"); routed {
+		t.Fatal("expected unit 15 not routed to a memory")
+	}
+}
+
+func TestUpstreamMap_Validate(t *testing.T) {
+	cfg := routingTestConfig(RoutingStrict)
+
+	cases := []struct {
+		name      string
+		upstreams UpstreamMap
+		err       string
+	}{
+		{"valid", UpstreamMap{{First: 10, Last: 19, Upstream: Upstream{Address: "10.0.0.1:502"}}}, ""},
+		{"missing port", UpstreamMap{{First: 10, Last: 10, Upstream: Upstream{Address: "10.0.0.1"}}}, "address invalid"},
+		{"overlaps unit_id_map", UpstreamMap{{First: 1, Last: 5, Upstream: Upstream{Address: "10.0.0.1:502"}}}, "overlaps unit_id_map"},
+		{"overlaps upstream", UpstreamMap{
+			{First: 10, Last: 19, Upstream: Upstream{Address: "10.0.0.1:502"}},
+			{First: 15, Last: 15, Upstream: Upstream{Address: "10.0.0.2:502"}},
+		}, "overlaps"},
+	}
+
+	for _, tc := range cases {
+		r := cfg.Routing
+		r.Upstreams = tc.upstreams
+
+		err := r.Validate(cfg.Memory)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
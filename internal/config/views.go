@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// ViewWindow presents part of a memory at another address, optionally
+// in another area. Once an area has a window on a port, it is reachable
+// only through its windows; other areas keep their normal addressing.
+type ViewWindow struct {
+	Area   string     `yaml:"area"`  // external area
+	Start  int        `yaml:"start"` // first external address
+	Length int        `yaml:"length"`
+	Target ViewTarget `yaml:"target"`
+}
+
+// ViewTarget locates a window inside the memory.
+type ViewTarget struct {
+	Area   string `yaml:"area,omitempty"` // default: same as the window
+	Offset int    `yaml:"offset"`         // zero-based index in the area
+}
+
+// TargetArea returns the memory area of the window.
+func (w ViewWindow) TargetArea() string {
+	if w.Target.Area == "" {
+		return w.Area
+	}
+	return w.Target.Area
+}
+
+// viewSources lists the memory areas each external area may show.
+// Writable areas only map onto themselves, so Modbus can never write
+// discrete inputs or input registers through a view.
+var viewSources = map[core.Area][]core.Area{
+	core.AreaCoils:          {core.AreaCoils},
+	core.AreaDiscreteInputs: {core.AreaDiscreteInputs, core.AreaCoils},
+	core.AreaHoldingRegs:    {core.AreaHoldingRegs},
+	core.AreaInputRegs:      {core.AreaInputRegs, core.AreaHoldingRegs},
+}
+
+func (c *AppConfig) validateViews(name string, p PortPolicy) error {
+	for i, w := range p.Views {
+		path := fmt.Sprintf("%s.views[%d]", name, i)
+
+		area, ok := core.ParseArea(w.Area)
+		if !ok {
+			return fmt.Errorf("%s.area invalid: %q", path, w.Area)
+		}
+		target, ok := core.ParseArea(w.TargetArea())
+		if !ok {
+			return fmt.Errorf("%s.target.area invalid: %q", path, w.Target.Area)
+		}
+
+		if !containsArea(viewSources[area], target) {
+			return fmt.Errorf("%s: %s cannot show %s", path, area, target)
+		}
+
+		if w.Length <= 0 {
+			return fmt.Errorf("%s.length must be > 0", path)
+		}
+		if w.Start < 0 || w.Start+w.Length > 65536 {
+			return fmt.Errorf("%s: window must lie within 0..65535", path)
+		}
+		if w.Target.Offset < 0 {
+			return fmt.Errorf("%s.target.offset must be >= 0", path)
+		}
+
+		// External windows of one area must not overlap
+		for j, prev := range p.Views[:i] {
+			if prev.Area == w.Area &&
+				w.Start < prev.Start+prev.Length && prev.Start < w.Start+w.Length {
+				return fmt.Errorf("%s overlaps %s.views[%d]", path, name, j)
+			}
+		}
+
+		// The target range must exist in every memory the port exposes
+		for memID, block := range c.Memory.Memories {
+			if !p.AllowsMemory(memID) {
+				continue
+			}
+			if w.Target.Offset+w.Length > block.areaSize(target) {
+				return fmt.Errorf(
+					"%s: target %s %d..%d exceeds memory '%s'",
+					path, target, w.Target.Offset, w.Target.Offset+w.Length-1, memID,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+func containsArea(list []core.Area, a core.Area) bool {
+	for _, x := range list {
+		if x == a {
+			return true
+		}
+	}
+	return false
+}
+
+// areaSize returns the configured size of one area.
+func (m MemoryBlock) areaSize(a core.Area) int {
+	switch a {
+	case core.AreaCoils:
+		return m.Coils.Size
+	case core.AreaDiscreteInputs:
+		return m.DiscreteInputs.Size
+	case core.AreaHoldingRegs:
+		return m.HoldingRegisters.Size
+	case core.AreaInputRegs:
+		return m.InputRegisters.Size
+	}
+	return 0
+}
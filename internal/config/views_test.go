@@ -0,0 +1,64 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateViews(t *testing.T) {
+	cfg := &AppConfig{
+		Memory: MemoryConfig{
+			Memories: map[string]MemoryBlock{
+				"memory1": {
+					Coils:            AreaConfig{Size: 16},
+					DiscreteInputs:   AreaConfig{Size: 16},
+					HoldingRegisters: AreaConfig{Size: 100},
+					InputRegisters:   AreaConfig{Size: 10},
+				},
+			},
+		},
+	}
+	base := PortPolicy{Memories: MemorySelector{All: true}}
+
+	cases := []struct {
+		name  string
+		views []ViewWindow
+		err   string
+	}{
+		{"mirror holding into input", []ViewWindow{
+			{Area: "input_registers", Start: 0, Length: 10, Target: ViewTarget{Area: "holding_registers", Offset: 90}},
+		}, ""},
+		{"unknown area", []ViewWindow{
+			{Area: "registers", Length: 1},
+		}, "area invalid"},
+		{"writable area onto read-only", []ViewWindow{
+			{Area: "holding_registers", Length: 1, Target: ViewTarget{Area: "input_registers"}},
+		}, "cannot show"},
+		{"zero length", []ViewWindow{
+			{Area: "coils", Start: 10},
+		}, "length"},
+		{"past address space", []ViewWindow{
+			{Area: "coils", Start: 65530, Length: 10},
+		}, "0..65535"},
+		{"target past memory", []ViewWindow{
+			{Area: "holding_registers", Start: 3000, Length: 20, Target: ViewTarget{Offset: 90}},
+		}, "exceeds memory"},
+		{"overlapping windows", []ViewWindow{
+			{Area: "coils", Start: 0, Length: 8},
+			{Area: "coils", Start: 4, Length: 8, Target: ViewTarget{Offset: 8}},
+		}, "overlaps"},
+	}
+
+	for _, tc := range cases {
+		p := base
+		p.Views = tc.views
+
+		err := cfg.validateViews("ports.502", p)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Fatalf("%s: expected error containing %q, got %v", tc.name, tc.err, err)
+		}
+	}
+}
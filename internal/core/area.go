@@ -0,0 +1,67 @@
+package core
+
+// ===========================
+// Areas & External Addressing
+// ===========================
+
+// Area identifies one of the four Modbus data areas of a memory.
+type Area uint8
+
+const (
+	AreaCoils Area = iota
+	AreaDiscreteInputs
+	AreaHoldingRegs
+	AreaInputRegs
+
+	areaCount
+)
+
+var areaNames = [areaCount]string{
+	AreaCoils:          "coils",
+	AreaDiscreteInputs: "discrete_inputs",
+	AreaHoldingRegs:    "holding_registers",
+	AreaInputRegs:      "input_registers",
+}
+
+// String returns the config name of the area (e.g. "holding_registers").
+func (a Area) String() string {
+	if a >= areaCount {
+		return "unknown"
+	}
+	return areaNames[a]
+}
+
+// ParseArea maps a config area name to an Area.
+func ParseArea(name string) (Area, bool) {
+	for a, n := range areaNames {
+		if n == name {
+			return Area(a), true
+		}
+	}
+	return 0, false
+}
+
+// SetAddressBase sets the external address of index 0 in an area
+// (AreaConfig.Start). It must be called before the memory is shared.
+func (m *Memory) SetAddressBase(area Area, start int) {
+	if area >= areaCount {
+		return
+	}
+	m.base[area] = start
+}
+
+// AddressBase returns the external address of index 0 in an area.
+func (m *Memory) AddressBase(area Area) int {
+	if area >= areaCount {
+		return 0
+	}
+	return m.base[area]
+}
+
+// ToInternal maps an external address into the zero-based index used by
+// the Read*/Write* methods. Addresses below the area start map to negative
+// indexes, so out-of-window access fails the bounds check with
+// ErrOutOfRange on either side.
+func (m *Memory) ToInternal(area Area, addr int) int {
+	return addr - m.AddressBase(area)
+}
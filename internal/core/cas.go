@@ -0,0 +1,42 @@
+package core
+
+// CompareAndSwapCoils writes values at index only if the current coils
+// equal expected; otherwise it returns ErrConflict.
+func (m *Memory) CompareAndSwapCoils(index int, expected, values []bool) error {
+	return m.apply(SourceUnknown, nil, []Op{casBits(AreaCoils, index, expected, values)})
+}
+
+// CompareAndSwapHoldingRegs writes values at index only if the current
+// holding registers equal expected; otherwise it returns ErrConflict.
+func (m *Memory) CompareAndSwapHoldingRegs(index int, expected, values []uint16) error {
+	return m.apply(SourceUnknown, nil, []Op{casRegs(AreaHoldingRegs, index, expected, values)})
+}
+
+// CompareAndSwapCoils is Memory.CompareAndSwapCoils attributed to the
+// writer's source.
+func (w Writer) CompareAndSwapCoils(index int, expected, values []bool) error {
+	return w.m.apply(w.src, nil, []Op{casBits(AreaCoils, index, expected, values)})
+}
+
+// CompareAndSwapHoldingRegs is Memory.CompareAndSwapHoldingRegs
+// attributed to the writer's source.
+func (w Writer) CompareAndSwapHoldingRegs(index int, expected, values []uint16) error {
+	return w.m.apply(w.src, nil, []Op{casRegs(AreaHoldingRegs, index, expected, values)})
+}
+
+// casBits and casRegs build a conditional op. A nil expected still
+// makes the op conditional, so a missing expectation is rejected rather
+// than written blindly.
+func casBits(area Area, index int, expected, values []bool) Op {
+	if expected == nil {
+		expected = []bool{}
+	}
+	return Op{Area: area, Index: index, Bits: values, ExpectBits: expected}
+}
+
+func casRegs(area Area, index int, expected, values []uint16) Op {
+	if expected == nil {
+		expected = []uint16{}
+	}
+	return Op{Area: area, Index: index, Registers: values, ExpectRegisters: expected}
+}
@@ -0,0 +1,125 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCompareAndSwapHoldingRegs(t *testing.T) {
+	mem := NewMemory(0, 0, 4, 0)
+	_ = mem.WriteHoldingRegs(0, []uint16{1, 2})
+
+	// Stale expectation: nothing written
+	if err := mem.CompareAndSwapHoldingRegs(0, []uint16{1, 3}, []uint16{9, 9}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if g := mem.Generation(); g != 1 {
+		t.Fatalf("conflict advanced generation to %d", g)
+	}
+
+	if err := mem.CompareAndSwapHoldingRegs(0, []uint16{1, 2}, []uint16{9, 9}); err != nil {
+		t.Fatal(err)
+	}
+	if hr, _ := mem.ReadHoldingRegs(0, 2); !slices.Equal(hr, []uint16{9, 9}) {
+		t.Fatalf("unexpected registers %v", hr)
+	}
+}
+
+func TestCompareAndSwapCoils(t *testing.T) {
+	mem := NewMemory(4, 0, 0, 0)
+
+	if err := mem.From(SourceREST).CompareAndSwapCoils(1, []bool{true}, []bool{false}); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if err := mem.From(SourceREST).CompareAndSwapCoils(1, []bool{false}, []bool{true}); err != nil {
+		t.Fatal(err)
+	}
+	if c, _ := mem.ReadCoils(1, 1); !c[0] {
+		t.Fatal("expected coil set")
+	}
+}
+
+func TestCompareAndSwap_Invalid(t *testing.T) {
+	mem := NewMemory(4, 0, 4, 0)
+
+	// Expected values must cover the payload
+	if err := mem.CompareAndSwapHoldingRegs(0, []uint16{0}, []uint16{1, 2}); !errors.Is(err, ErrInvalidOp) {
+		t.Fatalf("expected ErrInvalidOp, got %v", err)
+	}
+	if err := mem.CompareAndSwapCoils(0, nil, []bool{true}); !errors.Is(err, ErrInvalidOp) {
+		t.Fatalf("expected ErrInvalidOp for missing expectation, got %v", err)
+	}
+
+	// Range errors win over conflicts
+	if err := mem.CompareAndSwapHoldingRegs(3, []uint16{5, 5}, []uint16{1, 2}); !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestApplyIfUnmodified(t *testing.T) {
+	mem := NewMemory(0, 2, 2, 2)
+	_ = mem.WriteHoldingRegs(0, []uint16{1}) // generation 1
+	_ = mem.WriteInputRegs(0, []uint16{1})   // generation 2
+
+	at := func(gen uint64) Version { return Version{Epoch: mem.Epoch(), Generation: gen} }
+
+	// Holding registers unchanged since 1
+	if err := mem.ApplyIfUnmodified(at(1), []Op{{Area: AreaHoldingRegs, Registers: []uint16{5}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Now written at 3: a writer that read at 2 lost the race
+	err := mem.ApplyIfUnmodified(at(2), []Op{
+		{Area: AreaDiscreteInputs, Bits: []bool{true}},
+		{Area: AreaHoldingRegs, Registers: []uint16{6}},
+	})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if di, _ := mem.ReadDiscreteInputs(0, 1); di[0] {
+		t.Fatal("transaction partially applied")
+	}
+
+	// The memory generation of the latest read is always current
+	if err := mem.ApplyIfUnmodified(at(mem.Generation()), []Op{{Area: AreaHoldingRegs, Registers: []uint16{6}}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestApplyIfUnmodified_OtherEpoch(t *testing.T) {
+	mem := NewMemory(0, 0, 2, 0)
+
+	// Same generation, but handed out by another instance (or boot)
+	other := NewMemory(0, 0, 2, 0)
+	if mem.Epoch() == other.Epoch() {
+		t.Fatal("expected a new epoch per memory instance")
+	}
+
+	since := Version{Epoch: other.Epoch(), Generation: mem.Generation()}
+	err := mem.ApplyIfUnmodified(since, []Op{{Area: AreaHoldingRegs, Registers: []uint16{1}}})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	// Restoring a snapshot keeps the epoch of the running instance
+	epoch := mem.Epoch()
+	if err := mem.Restore(other.Snapshot()); err != nil || mem.Epoch() != epoch {
+		t.Fatalf("restore changed epoch (err %v)", err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	v := Version{Epoch: "a1b2c3d4e5f60718", Generation: 57}
+
+	got, err := ParseVersion(v.String())
+	if err != nil || got != v {
+		t.Fatalf("round trip %q: got %+v, %v", v.String(), got, err)
+	}
+
+	for _, s := range []string{"", "57", "-57", "a1b2-", "a1b2-x", "a1b2--1"} {
+		if _, err := ParseVersion(s); !errors.Is(err, ErrInvalidVersion) {
+			t.Fatalf("%q: expected ErrInvalidVersion, got %v", s, err)
+		}
+	}
+}
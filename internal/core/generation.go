@@ -0,0 +1,99 @@
+package core
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync/atomic"
+)
+
+// GenerationRegisters is the number of input registers that expose the
+// memory generation on Modbus: 64 bits, most significant word first.
+const GenerationRegisters = 4
+
+// generations count successful writes, per memory and per area. They
+// advance under the memory write lock and start at 0 on boot, or at
+// the generations of a restored snapshot.
+type generations struct {
+	memory atomic.Uint64
+	area   [areaCount]atomic.Uint64
+
+	// Internal input register index of the generation registers
+	// (-1 = not exposed)
+	register int
+
+	// epoch identifies this memory instance. Generations restart (or
+	// roll back to a snapshot) across boots, so a generation is only
+	// meaningful together with its epoch.
+	epoch string
+}
+
+// Epoch returns the random identifier of this memory instance, new on
+// every boot.
+func (m *Memory) Epoch() string {
+	return m.gen.epoch
+}
+
+// Generation returns the number of successful writes to the memory.
+func (m *Memory) Generation() uint64 {
+	return m.gen.memory.Load()
+}
+
+// AreaGeneration returns the memory generation of the last successful
+// write to area, or 0 when the area was never written.
+func (m *Memory) AreaGeneration(area Area) uint64 {
+	if area >= areaCount {
+		return 0
+	}
+	return m.gen.area[area].Load()
+}
+
+// SetGenerationRegister exposes the memory generation as the
+// GenerationRegisters input registers starting at index. Those
+// registers become read-only. It must be called before the memory is
+// shared.
+func (m *Memory) SetGenerationRegister(index int) error {
+	if err := checkUint16Range(m.InputRegs, index, GenerationRegisters); err != nil {
+		return err
+	}
+	m.gen.register = index
+	return nil
+}
+
+// advance records a successful write to area and returns the new
+// memory generation. Caller holds m.mu for writing.
+func (m *Memory) advance(area Area) uint64 {
+	g := m.gen.memory.Add(1)
+	m.gen.area[area].Store(g)
+	return g
+}
+
+// coversGeneration reports whether [index, index+count) of the input
+// registers touches the generation registers.
+func (m *Memory) coversGeneration(index, count int) bool {
+	r := m.gen.register
+	return r >= 0 && index < r+GenerationRegisters && r < index+count
+}
+
+// overlayGeneration replaces the generation registers inside out, which
+// holds the input registers starting at index. Caller holds m.mu.
+func (m *Memory) overlayGeneration(out []uint16, index int) {
+	if !m.coversGeneration(index, len(out)) {
+		return
+	}
+
+	var word [8]byte
+	binary.BigEndian.PutUint64(word[:], m.gen.memory.Load())
+
+	for i := range GenerationRegisters {
+		if j := m.gen.register + i - index; j >= 0 && j < len(out) {
+			out[j] = binary.BigEndian.Uint16(word[2*i:])
+		}
+	}
+}
+
+func newEpoch() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:]) // never fails
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,85 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGeneration_AdvancesOnSuccessfulWrites(t *testing.T) {
+	mem := NewMemory(4, 4, 4, 4)
+
+	_ = mem.WriteCoils(0, []bool{true})
+	_ = mem.WriteHoldingRegs(0, []uint16{1})
+	_ = mem.MaskWriteHoldingReg(0, 0, 2)
+	_ = mem.WriteHoldingRegs(9, []uint16{1}) // out of range: no advance
+
+	if g := mem.Generation(); g != 3 {
+		t.Fatalf("expected generation 3, got %d", g)
+	}
+	if g := mem.AreaGeneration(AreaCoils); g != 1 {
+		t.Fatalf("expected coils generation 1, got %d", g)
+	}
+	if g := mem.AreaGeneration(AreaHoldingRegs); g != 3 {
+		t.Fatalf("expected holding generation 3, got %d", g)
+	}
+	if g := mem.AreaGeneration(AreaInputRegs); g != 0 {
+		t.Fatalf("expected untouched area at 0, got %d", g)
+	}
+
+	s := mem.Snapshot()
+	if s.Generation != 3 || s.AreaGenerations[AreaCoils] != 1 || s.AreaGenerations[AreaHoldingRegs] != 3 {
+		t.Fatalf("unexpected snapshot generations %d %v", s.Generation, s.AreaGenerations)
+	}
+}
+
+func TestReadVersioned(t *testing.T) {
+	mem := NewMemory(4, 4, 4, 4)
+	_ = mem.WriteInputRegs(1, []uint16{7, 8})
+	_ = mem.WriteCoils(0, []bool{true})
+
+	v, err := mem.ReadVersioned(AreaInputRegs, 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(v.Registers, []uint16{7, 8}) || v.Generation != 2 || v.AreaGeneration != 1 {
+		t.Fatalf("unexpected read %+v", v)
+	}
+
+	if _, err := mem.ReadVersioned(AreaCoils, 3, 2); err != ErrOutOfRange {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+}
+
+func TestGenerationRegister(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 8)
+	if err := mem.SetGenerationRegister(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.SetGenerationRegister(6); err == nil {
+		t.Fatal("expected registers past the end rejected")
+	}
+
+	for range 0x10002 {
+		_ = mem.WriteCoils(0, []bool{true})
+	}
+
+	// 0x10002 = 0000 0000 0001 0002, high word first
+	regs, _ := mem.ReadInputRegs(0, 8)
+	if want := []uint16{0, 0, 0, 0, 1, 2, 0, 0}; !slices.Equal(regs, want) {
+		t.Fatalf("expected %v, got %v", want, regs)
+	}
+
+	// Partial reads see their part of the value
+	regs, _ = mem.ReadInputRegs(5, 2)
+	if want := []uint16{2, 0}; !slices.Equal(regs, want) {
+		t.Fatalf("expected %v, got %v", want, regs)
+	}
+
+	// Read-only
+	if err := mem.WriteInputRegs(4, []uint16{9, 9}); err != ErrOutOfRange {
+		t.Fatalf("expected write rejected, got %v", err)
+	}
+	if err := mem.WriteInputRegs(6, []uint16{9, 9}); err != nil {
+		t.Fatalf("expected write beside the registers allowed, got %v", err)
+	}
+}
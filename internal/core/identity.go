@@ -0,0 +1,25 @@
+package core
+
+// ===========================
+// Device Identification
+// ===========================
+
+// SetDeviceIdentity attaches Modbus device identification objects
+// (object ID -> value) to the memory. It is set once at boot and is
+// read-only afterwards.
+func (m *Memory) SetDeviceIdentity(objects map[uint8]string) {
+	if len(objects) == 0 {
+		return
+	}
+
+	m.identity = make(map[uint8]string, len(objects))
+	for id, v := range objects {
+		m.identity[id] = v
+	}
+}
+
+// DeviceIdentity returns the identification objects, or nil when
+// no identity is configured for this memory. Callers must not modify it.
+func (m *Memory) DeviceIdentity() map[uint8]string {
+	return m.identity
+}
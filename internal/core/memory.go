@@ -36,6 +36,17 @@ type Memory struct {
 	state RunState
 	seal  *StateSealingGate
 
+	identity map[uint8]string
+
+	// External address of index 0, per Area (see area.go)
+	base [areaCount]int
+
+	// Write generations (see generation.go)
+	gen generations
+
+	// Change subscribers (see notify.go)
+	notify notifier
+
 	mu sync.RWMutex
 }
 
@@ -54,6 +65,7 @@ func NewMemory(
 		HoldingRegs:    make([]uint16, holdingCount),
 		InputRegs:      make([]uint16, inputCount),
 		state:          StateRun,
+		gen:            generations{register: -1, epoch: newEpoch()},
 	}
 }
 
@@ -87,6 +99,19 @@ func (m *Memory) GateAddress() int {
 	return m.seal.GateAddr
 }
 
+// Seal moves a PRE-RUN memory to RUN without a gate write, e.g. after
+// its values were restored from a snapshot.
+func (m *Memory) Seal() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.HasStateSealing() || m.state != StatePreRun {
+		return
+	}
+	m.state = StateRun
+	log.Printf("[STATE] memory sealed to RUN")
+}
+
 // ===========================
 // Internal sealing transition
 // ===========================
@@ -121,6 +146,10 @@ func (m *Memory) ReadCoils(addr, count int) ([]bool, error) {
 }
 
 func (m *Memory) WriteCoils(addr int, values []bool) error {
+	return m.writeCoils(SourceUnknown, addr, values)
+}
+
+func (m *Memory) writeCoils(src Source, addr int, values []bool) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -129,6 +158,7 @@ func (m *Memory) WriteCoils(addr int, values []bool) error {
 	}
 
 	copy(m.Coils[addr:], values)
+	m.commitBits(AreaCoils, addr, values, src)
 	return nil
 }
 
@@ -149,6 +179,10 @@ func (m *Memory) ReadDiscreteInputs(addr, count int) ([]bool, error) {
 }
 
 func (m *Memory) WriteDiscreteInputs(addr int, values []bool) error {
+	return m.writeDiscreteInputs(SourceUnknown, addr, values)
+}
+
+func (m *Memory) writeDiscreteInputs(src Source, addr int, values []bool) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -157,6 +191,7 @@ func (m *Memory) WriteDiscreteInputs(addr int, values []bool) error {
 	}
 
 	copy(m.DiscreteInputs[addr:], values)
+	m.commitBits(AreaDiscreteInputs, addr, values, src)
 
 	// 🔒 State Sealing gate check
 	m.transitionToRunIfGateHit(addr, values)
@@ -181,6 +216,10 @@ func (m *Memory) ReadHoldingRegs(addr, count int) ([]uint16, error) {
 }
 
 func (m *Memory) WriteHoldingRegs(addr int, values []uint16) error {
+	return m.writeHoldingRegs(SourceUnknown, addr, values)
+}
+
+func (m *Memory) writeHoldingRegs(src Source, addr int, values []uint16) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -189,9 +228,67 @@ func (m *Memory) WriteHoldingRegs(addr int, values []uint16) error {
 	}
 
 	copy(m.HoldingRegs[addr:], values)
+	m.commitRegs(AreaHoldingRegs, addr, values, src)
 	return nil
 }
 
+// MaskWriteHoldingReg applies (current AND andMask) OR (orMask AND NOT andMask)
+// to a single holding register as one atomic read-modify-write.
+func (m *Memory) MaskWriteHoldingReg(addr int, andMask, orMask uint16) error {
+	return m.maskWriteHoldingReg(SourceUnknown, addr, andMask, orMask)
+}
+
+func (m *Memory) maskWriteHoldingReg(src Source, addr int, andMask, orMask uint16) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := checkUint16Range(m.HoldingRegs, addr, 1); err != nil {
+		return err
+	}
+
+	cur := m.HoldingRegs[addr]
+	m.HoldingRegs[addr] = (cur & andMask) | (orMask &^ andMask)
+	m.commitRegs(AreaHoldingRegs, addr, m.HoldingRegs[addr:addr+1], src)
+	return nil
+}
+
+// WriteReadHoldingRegs writes values at writeAddr and then reads count
+// registers at readAddr under a single lock (FC 0x17 semantics).
+// Both ranges are validated before anything is written.
+func (m *Memory) WriteReadHoldingRegs(
+	writeAddr int,
+	values []uint16,
+	readAddr int,
+	count int,
+) ([]uint16, error) {
+	return m.writeReadHoldingRegs(SourceUnknown, writeAddr, values, readAddr, count)
+}
+
+func (m *Memory) writeReadHoldingRegs(
+	src Source,
+	writeAddr int,
+	values []uint16,
+	readAddr int,
+	count int,
+) ([]uint16, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := checkUint16Range(m.HoldingRegs, writeAddr, len(values)); err != nil {
+		return nil, err
+	}
+	if err := checkUint16Range(m.HoldingRegs, readAddr, count); err != nil {
+		return nil, err
+	}
+
+	copy(m.HoldingRegs[writeAddr:], values)
+	m.commitRegs(AreaHoldingRegs, writeAddr, values, src)
+
+	out := make([]uint16, count)
+	copy(out, m.HoldingRegs[readAddr:readAddr+count])
+	return out, nil
+}
+
 // ===========================
 // INPUT REGISTERS
 // ===========================
@@ -205,17 +302,27 @@ func (m *Memory) ReadInputRegs(addr, count int) ([]uint16, error) {
 
 	out := make([]uint16, count)
 	copy(out, m.InputRegs[addr:addr+count])
+	m.overlayGeneration(out, addr)
 	return out, nil
 }
 
 func (m *Memory) WriteInputRegs(addr int, values []uint16) error {
+	return m.writeInputRegs(SourceUnknown, addr, values)
+}
+
+func (m *Memory) writeInputRegs(src Source, addr int, values []uint16) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if err := checkUint16Range(m.InputRegs, addr, len(values)); err != nil {
 		return err
 	}
+	// Generation registers are read-only
+	if m.coversGeneration(addr, len(values)) {
+		return ErrOutOfRange
+	}
 
 	copy(m.InputRegs[addr:], values)
+	m.commitRegs(AreaInputRegs, addr, values, src)
 	return nil
 }
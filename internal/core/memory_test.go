@@ -0,0 +1,32 @@
+package core
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMaskWriteHoldingReg_ConcurrentBitsDoNotClobber(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 1)
+
+	var wg sync.WaitGroup
+	for bit := 0; bit < 16; bit++ {
+		wg.Add(1)
+		go func(bit int) {
+			defer wg.Done()
+			mask := uint16(1) << bit
+			for i := 0; i < 1000; i++ {
+				// set own bit, keep all others
+				if err := mem.MaskWriteHoldingReg(0, ^mask, mask); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(bit)
+	}
+	wg.Wait()
+
+	vals, _ := mem.ReadHoldingRegs(0, 1)
+	if vals[0] != 0xFFFF {
+		t.Fatalf("expected all bits set, got 0x%04X", vals[0])
+	}
+}
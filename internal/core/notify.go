@@ -0,0 +1,158 @@
+package core
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultSubscriptionBuffer is the channel size of a subscription when
+// none is given.
+const DefaultSubscriptionBuffer = 256
+
+// Change describes one successful write. Values hold the new contents
+// of [Index, Index+Count); they are shared by all subscribers and must
+// not be modified.
+type Change struct {
+	Area    Area
+	Index   int // zero-based
+	Address int // external (Index + AddressBase)
+	Count   int
+
+	Bits      []bool   // coils, discrete inputs
+	Registers []uint16 // holding and input registers
+
+	Source Source
+
+	// Generation is the memory generation after this write.
+	Generation uint64
+}
+
+// Subscription receives the changes of one memory on C, in write order.
+// Delivery never blocks a writer: when C is full the change is dropped
+// and counted.
+type Subscription struct {
+	C <-chan Change
+
+	ch      chan Change
+	m       *Memory
+	dropped atomic.Uint64
+}
+
+// notifier fans changes out to subscribers. Publishing runs under the
+// memory write lock, so events are ordered like the writes themselves.
+type notifier struct {
+	mu      sync.Mutex
+	subs    []*Subscription
+	active  atomic.Int32 // len(subs), read without mu on the write path
+	dropped atomic.Uint64
+}
+
+// Subscribe registers for change events. buffer bounds the events
+// queued for a slow receiver (0 = DefaultSubscriptionBuffer).
+func (m *Memory) Subscribe(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriptionBuffer
+	}
+
+	ch := make(chan Change, buffer)
+	s := &Subscription{C: ch, ch: ch, m: m}
+
+	n := &m.notify
+	n.mu.Lock()
+	n.subs = append(n.subs, s)
+	n.active.Store(int32(len(n.subs)))
+	n.mu.Unlock()
+
+	return s
+}
+
+// Close unsubscribes and closes C. Events still queued can be drained.
+func (s *Subscription) Close() {
+	n := &s.m.notify
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for i, sub := range n.subs {
+		if sub == s {
+			n.subs = append(n.subs[:i:i], n.subs[i+1:]...)
+			n.active.Store(int32(len(n.subs)))
+			close(s.ch)
+			return
+		}
+	}
+}
+
+// Dropped returns the changes this subscription missed because C was full.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// Subscribers returns the number of open subscriptions.
+func (m *Memory) Subscribers() int {
+	return int(m.notify.active.Load())
+}
+
+// DroppedChanges returns the changes dropped across all subscriptions.
+func (m *Memory) DroppedChanges() uint64 {
+	return m.notify.dropped.Load()
+}
+
+// commitBits and commitRegs record a successful write: they advance
+// the generations and notify subscribers. The write methods call them
+// with m.mu held. values is copied.
+func (m *Memory) commitBits(area Area, index int, values []bool, src Source) {
+	m.publishBits(area, index, values, src, m.advance(area))
+}
+
+func (m *Memory) commitRegs(area Area, index int, values []uint16, src Source) {
+	m.publishRegs(area, index, values, src, m.advance(area))
+}
+
+// publishBits and publishRegs notify subscribers of a write that
+// produced generation gen.
+func (m *Memory) publishBits(area Area, index int, values []bool, src Source, gen uint64) {
+	if m.notify.active.Load() == 0 {
+		return
+	}
+	m.publish(Change{
+		Area:       area,
+		Index:      index,
+		Address:    index + m.base[area],
+		Count:      len(values),
+		Bits:       append([]bool(nil), values...),
+		Source:     src,
+		Generation: gen,
+	})
+}
+
+func (m *Memory) publishRegs(area Area, index int, values []uint16, src Source, gen uint64) {
+	if m.notify.active.Load() == 0 {
+		return
+	}
+	m.publish(Change{
+		Area:       area,
+		Index:      index,
+		Address:    index + m.base[area],
+		Count:      len(values),
+		Registers:  append([]uint16(nil), values...),
+		Source:     src,
+		Generation: gen,
+	})
+}
+
+// publish offers c to every subscriber without blocking. Subscribers
+// share the values of c and must not modify them.
+func (m *Memory) publish(c Change) {
+	n := &m.notify
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, s := range n.subs {
+		select {
+		case s.ch <- c:
+		default:
+			s.dropped.Add(1)
+			n.dropped.Add(1)
+		}
+	}
+}
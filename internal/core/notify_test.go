@@ -0,0 +1,112 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSubscribe_DeliversChanges(t *testing.T) {
+	mem := NewMemory(8, 8, 8, 8)
+	mem.SetAddressBase(AreaHoldingRegs, 100)
+
+	sub := mem.Subscribe(4)
+	defer sub.Close()
+
+	if err := mem.From(SourceModbus).WriteHoldingRegs(2, []uint16{7, 8}); err != nil {
+		t.Fatal(err)
+	}
+	if err := mem.WriteCoils(1, []bool{true}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := <-sub.C
+	if c.Area != AreaHoldingRegs || c.Index != 2 || c.Address != 102 || c.Count != 2 ||
+		!slices.Equal(c.Registers, []uint16{7, 8}) || c.Source != SourceModbus {
+		t.Fatalf("unexpected change %+v", c)
+	}
+
+	c = <-sub.C
+	if c.Area != AreaCoils || !slices.Equal(c.Bits, []bool{true}) || c.Source != SourceUnknown {
+		t.Fatalf("unexpected change %+v", c)
+	}
+}
+
+func TestSubscribe_MaskWriteCarriesResult(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 1)
+	_ = mem.WriteHoldingRegs(0, []uint16{0x00F0})
+
+	sub := mem.Subscribe(1)
+	defer sub.Close()
+
+	if err := mem.MaskWriteHoldingReg(0, 0xFF00, 0x0001); err != nil {
+		t.Fatal(err)
+	}
+	if c := <-sub.C; c.Registers[0] != 0x0001 {
+		t.Fatalf("expected new value 0x0001, got 0x%04X", c.Registers[0])
+	}
+}
+
+func TestSubscribe_FailedWriteNotPublished(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 1)
+	sub := mem.Subscribe(1)
+	defer sub.Close()
+
+	if err := mem.WriteInputRegs(0, []uint16{1, 2}); err == nil {
+		t.Fatal("expected out of range")
+	}
+	select {
+	case c := <-sub.C:
+		t.Fatalf("unexpected change %+v", c)
+	default:
+	}
+}
+
+func TestSubscribe_SlowSubscriberNeverBlocksWriter(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 1)
+	slow := mem.Subscribe(2)
+	defer slow.Close()
+	fast := mem.Subscribe(10)
+	defer fast.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := range 10 {
+			_ = mem.WriteHoldingRegs(0, []uint16{uint16(i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("writer blocked by full subscriber")
+	}
+
+	if slow.Dropped() != 8 || fast.Dropped() != 0 || mem.DroppedChanges() != 8 {
+		t.Fatalf("expected 8 drops on slow only, got slow=%d fast=%d total=%d",
+			slow.Dropped(), fast.Dropped(), mem.DroppedChanges())
+	}
+	if len(fast.C) != 10 {
+		t.Fatalf("expected all 10 changes on fast subscriber, got %d", len(fast.C))
+	}
+}
+
+func TestSubscription_Close(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 1)
+	sub := mem.Subscribe(1)
+	sub.Close()
+	sub.Close() // idempotent
+
+	if mem.Subscribers() != 0 {
+		t.Fatalf("expected no subscribers, got %d", mem.Subscribers())
+	}
+	if _, ok := <-sub.C; ok {
+		t.Fatal("expected closed channel")
+	}
+
+	// Writes after close are fine
+	if err := mem.WriteCoils(0, []bool{true}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,20 +1,148 @@
 package core
 
+import "errors"
+
+// ErrSnapshotMismatch is returned by Restore for a snapshot whose area
+// sizes differ from the memory.
+var ErrSnapshotMismatch = errors.New("snapshot does not match memory layout")
+
+// Snapshot is a consistent copy of a memory: every value and generation
+// is taken under one read lock.
 type Snapshot struct {
 	Coils          []bool
 	DiscreteInputs []bool
 	HoldingRegs    []uint16
 	InputRegs      []uint16
+
+	// Generation is the memory generation the values reflect;
+	// AreaGenerations are indexed by Area.
+	Generation      uint64
+	AreaGenerations [areaCount]uint64
+
+	// Sealed reports that the memory was not in PRE-RUN, i.e. its
+	// values were complete rather than still being loaded.
+	Sealed bool
 }
 
 func (m *Memory) Snapshot() Snapshot {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return Snapshot{
+	s := Snapshot{
 		Coils:          append([]bool(nil), m.Coils...),
 		DiscreteInputs: append([]bool(nil), m.DiscreteInputs...),
 		HoldingRegs:    append([]uint16(nil), m.HoldingRegs...),
 		InputRegs:      append([]uint16(nil), m.InputRegs...),
+		Generation:     m.gen.memory.Load(),
+		Sealed:         !m.IsPreRun(),
 	}
+	m.overlayGeneration(s.InputRegs, 0)
+
+	for a := range s.AreaGenerations {
+		s.AreaGenerations[a] = m.gen.area[a].Load()
+	}
+	return s
+}
+
+// Restore replaces every value and generation with those of s, e.g. a
+// snapshot retained across a restart; generations continue from s. The
+// epoch is kept: versions handed out before the restart stay invalid.
+// It neither notifies subscribers nor passes the State Sealing gate,
+// whatever s.Sealed says, and is meant for boot, before the memory is
+// shared.
+func (m *Memory) Restore(s Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(s.Coils) != len(m.Coils) ||
+		len(s.DiscreteInputs) != len(m.DiscreteInputs) ||
+		len(s.HoldingRegs) != len(m.HoldingRegs) ||
+		len(s.InputRegs) != len(m.InputRegs) {
+		return ErrSnapshotMismatch
+	}
+
+	copy(m.Coils, s.Coils)
+	copy(m.DiscreteInputs, s.DiscreteInputs)
+	copy(m.HoldingRegs, s.HoldingRegs)
+	copy(m.InputRegs, s.InputRegs)
+
+	// The snapshot carries the generation overlay; the registers
+	// behind it are never written
+	if r := m.gen.register; r >= 0 {
+		clear(m.InputRegs[r : r+GenerationRegisters])
+	}
+
+	m.gen.memory.Store(s.Generation)
+	for a := range s.AreaGenerations {
+		m.gen.area[a].Store(s.AreaGenerations[a])
+	}
+	return nil
+}
+
+// Versioned is one area read together with the generations it reflects.
+type Versioned struct {
+	Bits      []bool   // coils, discrete inputs
+	Registers []uint16 // holding and input registers
+
+	Generation     uint64
+	AreaGeneration uint64
+
+	// Epoch of the memory instance, see Version
+	Epoch string
+
+	// overlaid: the values include the generation registers
+	overlaid bool
+}
+
+// Version returns the area version the values reflect. Values that
+// include the generation registers change with every write to the
+// memory, so their version is the memory generation.
+func (v Versioned) Version() Version {
+	if v.overlaid {
+		return Version{Epoch: v.Epoch, Generation: v.Generation}
+	}
+	return Version{Epoch: v.Epoch, Generation: v.AreaGeneration}
+}
+
+// ReadVersioned reads count values at index of area and the generations
+// they reflect under one read lock.
+func (m *Memory) ReadVersioned(area Area, index, count int) (Versioned, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var v Versioned
+
+	switch area {
+	case AreaCoils, AreaDiscreteInputs:
+		src := m.Coils
+		if area == AreaDiscreteInputs {
+			src = m.DiscreteInputs
+		}
+		if err := checkBoolRange(src, index, count); err != nil {
+			return Versioned{}, err
+		}
+		v.Bits = append([]bool(nil), src[index:index+count]...)
+
+	case AreaHoldingRegs, AreaInputRegs:
+		src := m.HoldingRegs
+		if area == AreaInputRegs {
+			src = m.InputRegs
+		}
+		if err := checkUint16Range(src, index, count); err != nil {
+			return Versioned{}, err
+		}
+		v.Registers = append([]uint16(nil), src[index:index+count]...)
+		if area == AreaInputRegs {
+			m.overlayGeneration(v.Registers, index)
+			v.overlaid = m.coversGeneration(index, count)
+		}
+
+	default:
+		return Versioned{}, ErrOutOfRange
+	}
+
+	v.Generation = m.gen.memory.Load()
+	v.AreaGeneration = m.gen.area[area].Load()
+	v.Epoch = m.gen.epoch
+	return v, nil
 }
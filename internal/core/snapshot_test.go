@@ -0,0 +1,78 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRestore_RoundTrip(t *testing.T) {
+	src := NewMemory(2, 2, 2, 8)
+	_ = src.SetGenerationRegister(4)
+	_ = src.WriteCoils(1, []bool{true})
+	_ = src.WriteHoldingRegs(0, []uint16{7, 8})
+	_ = src.WriteInputRegs(0, []uint16{1, 2, 3})
+
+	dst := NewMemory(2, 2, 2, 8)
+	_ = dst.SetGenerationRegister(4)
+
+	sub := dst.Subscribe(1)
+	defer sub.Close()
+
+	if err := dst.Restore(src.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+
+	got, want := dst.Snapshot(), src.Snapshot()
+	if !slices.Equal(got.Coils, want.Coils) ||
+		!slices.Equal(got.HoldingRegs, want.HoldingRegs) ||
+		!slices.Equal(got.InputRegs, want.InputRegs) ||
+		got.AreaGenerations != want.AreaGenerations {
+		t.Fatalf("restored %+v, want %+v", got, want)
+	}
+
+	// Generations continue from the snapshot
+	_ = dst.WriteCoils(0, []bool{true})
+	if g := dst.Generation(); g != want.Generation+1 {
+		t.Fatalf("expected generation %d, got %d", want.Generation+1, g)
+	}
+
+	// The registers behind the overlay stay untouched
+	if r := dst.InputRegs[4:8]; !slices.Equal(r, make([]uint16, 4)) {
+		t.Fatalf("generation registers stored %v", r)
+	}
+
+	// Restore itself is not a change
+	if c := <-sub.C; c.Area != AreaCoils || c.Index != 0 {
+		t.Fatalf("unexpected change %+v", c)
+	}
+}
+
+func TestRestore_LayoutMismatch(t *testing.T) {
+	snap := NewMemory(2, 2, 2, 2).Snapshot()
+
+	mem := NewMemory(2, 2, 3, 2)
+	if err := mem.Restore(snap); !errors.Is(err, ErrSnapshotMismatch) {
+		t.Fatalf("expected ErrSnapshotMismatch, got %v", err)
+	}
+}
+
+func TestRestore_DoesNotPassGate(t *testing.T) {
+	src := NewMemory(0, 4, 0, 0)
+	_ = src.WriteDiscreteInputs(2, []bool{true})
+
+	mem := NewMemory(0, 4, 0, 0)
+	mem.SetStateSealing(true, 2)
+
+	if err := mem.Restore(src.Snapshot()); err != nil {
+		t.Fatal(err)
+	}
+	if !mem.IsPreRun() {
+		t.Fatal("restore sealed the memory")
+	}
+
+	mem.Seal()
+	if mem.IsPreRun() {
+		t.Fatal("expected RUN after Seal")
+	}
+}
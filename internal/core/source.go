@@ -0,0 +1,49 @@
+package core
+
+// Source names the origin of a write in change events.
+type Source string
+
+const (
+	SourceUnknown   Source = ""
+	SourceModbus    Source = "modbus"
+	SourceREST      Source = "rest"
+	SourceMQTT      Source = "mqtt"
+	SourceRawIngest Source = "raw_ingest"
+	SourcePoller    Source = "poller"
+)
+
+// Writer writes to a memory on behalf of one source. The write methods
+// behave exactly like those of Memory; change events carry the source.
+type Writer struct {
+	m   *Memory
+	src Source
+}
+
+// From returns a writer whose changes are attributed to src.
+func (m *Memory) From(src Source) Writer {
+	return Writer{m: m, src: src}
+}
+
+func (w Writer) WriteCoils(addr int, values []bool) error {
+	return w.m.writeCoils(w.src, addr, values)
+}
+
+func (w Writer) WriteDiscreteInputs(addr int, values []bool) error {
+	return w.m.writeDiscreteInputs(w.src, addr, values)
+}
+
+func (w Writer) WriteHoldingRegs(addr int, values []uint16) error {
+	return w.m.writeHoldingRegs(w.src, addr, values)
+}
+
+func (w Writer) MaskWriteHoldingReg(addr int, andMask, orMask uint16) error {
+	return w.m.maskWriteHoldingReg(w.src, addr, andMask, orMask)
+}
+
+func (w Writer) WriteReadHoldingRegs(writeAddr int, values []uint16, readAddr int, count int) ([]uint16, error) {
+	return w.m.writeReadHoldingRegs(w.src, writeAddr, values, readAddr, count)
+}
+
+func (w Writer) WriteInputRegs(addr int, values []uint16) error {
+	return w.m.writeInputRegs(w.src, addr, values)
+}
@@ -0,0 +1,199 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrInvalidOp is returned for a transaction op whose payload is
+	// empty or does not match its area, and for an empty transaction.
+	ErrInvalidOp = errors.New("invalid transaction op")
+
+	// ErrConflict is returned when the condition of a conditional write
+	// no longer holds. Nothing was written.
+	ErrConflict = errors.New("write condition failed")
+)
+
+// OpError reports which op of a transaction was rejected. It wraps
+// ErrInvalidOp or ErrOutOfRange.
+type OpError struct {
+	Index int // position in the ops slice
+	Err   error
+}
+
+func (e *OpError) Error() string {
+	return fmt.Sprintf("op %d: %v", e.Index, e.Err)
+}
+
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// Op is one write of a transaction. Bits carries coils and discrete
+// inputs, Registers holding and input registers.
+//
+// An op with ExpectBits or ExpectRegisters is conditional: it applies
+// only while the current values equal them, see ErrConflict. Expected
+// values cover the same range as the payload.
+type Op struct {
+	Area      Area
+	Index     int // zero-based
+	Bits      []bool
+	Registers []uint16
+
+	ExpectBits      []bool
+	ExpectRegisters []uint16
+}
+
+// Apply performs ops as one atomic write across any areas. Every op is
+// checked before anything is written, a rejected one as *OpError;
+// readers observe all of them or none. Ops apply in order, so a later op wins where ranges overlap.
+// The transaction advances the generation once and notifies one change
+// per op.
+func (m *Memory) Apply(ops []Op) error {
+	return m.apply(SourceUnknown, nil, ops)
+}
+
+// ApplyIfUnmodified performs ops like Apply, but only if since is of
+// this memory's epoch and no area they touch was written after its
+// generation; otherwise it returns ErrConflict. since is a memory or
+// area version from an earlier read.
+func (m *Memory) ApplyIfUnmodified(since Version, ops []Op) error {
+	return m.apply(SourceUnknown, &since, ops)
+}
+
+// Apply performs ops as one atomic write, see Memory.Apply.
+func (w Writer) Apply(ops []Op) error {
+	return w.m.apply(w.src, nil, ops)
+}
+
+// ApplyIfUnmodified performs ops conditionally, see
+// Memory.ApplyIfUnmodified.
+func (w Writer) ApplyIfUnmodified(since Version, ops []Op) error {
+	return w.m.apply(w.src, &since, ops)
+}
+
+// apply performs ops atomically; with since, only if the touched areas
+// are unmodified since that version.
+func (m *Memory) apply(src Source, since *Version, ops []Op) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(ops) == 0 {
+		return ErrInvalidOp
+	}
+	for i, op := range ops {
+		if err := m.checkOp(op); err != nil {
+			return &OpError{Index: i, Err: err}
+		}
+	}
+
+	// Conditions are checked only once every op is known to be valid.
+	// A version of another epoch may name a state of a previous boot.
+	if since != nil && since.Epoch != m.gen.epoch {
+		return ErrConflict
+	}
+	for _, op := range ops {
+		if since != nil && m.gen.area[op.Area].Load() > since.Generation {
+			return ErrConflict
+		}
+		if !m.matches(op) {
+			return ErrConflict
+		}
+	}
+
+	gen := m.gen.memory.Add(1)
+
+	for _, op := range ops {
+		switch op.Area {
+		case AreaCoils:
+			copy(m.Coils[op.Index:], op.Bits)
+		case AreaDiscreteInputs:
+			copy(m.DiscreteInputs[op.Index:], op.Bits)
+
+			// 🔒 State Sealing gate check
+			m.transitionToRunIfGateHit(op.Index, op.Bits)
+		case AreaHoldingRegs:
+			copy(m.HoldingRegs[op.Index:], op.Registers)
+		case AreaInputRegs:
+			copy(m.InputRegs[op.Index:], op.Registers)
+		}
+
+		m.gen.area[op.Area].Store(gen)
+
+		switch op.Area {
+		case AreaCoils, AreaDiscreteInputs:
+			m.publishBits(op.Area, op.Index, op.Bits, src, gen)
+		default:
+			m.publishRegs(op.Area, op.Index, op.Registers, src, gen)
+		}
+	}
+
+	return nil
+}
+
+// checkOp validates one op against the memory. Caller holds m.mu.
+func (m *Memory) checkOp(op Op) error {
+	switch op.Area {
+	case AreaCoils, AreaDiscreteInputs:
+		if len(op.Bits) == 0 || op.Registers != nil || op.ExpectRegisters != nil {
+			return ErrInvalidOp
+		}
+		if op.ExpectBits != nil && len(op.ExpectBits) != len(op.Bits) {
+			return ErrInvalidOp
+		}
+		dst := m.Coils
+		if op.Area == AreaDiscreteInputs {
+			dst = m.DiscreteInputs
+		}
+		return checkBoolRange(dst, op.Index, len(op.Bits))
+
+	case AreaHoldingRegs:
+		if !regsOp(op) {
+			return ErrInvalidOp
+		}
+		return checkUint16Range(m.HoldingRegs, op.Index, len(op.Registers))
+
+	case AreaInputRegs:
+		if !regsOp(op) {
+			return ErrInvalidOp
+		}
+		if err := checkUint16Range(m.InputRegs, op.Index, len(op.Registers)); err != nil {
+			return err
+		}
+		// Generation registers are read-only
+		if m.coversGeneration(op.Index, len(op.Registers)) {
+			return ErrOutOfRange
+		}
+		return nil
+
+	default:
+		return ErrInvalidOp
+	}
+}
+
+// regsOp reports whether op carries a well-formed register payload.
+func regsOp(op Op) bool {
+	if len(op.Registers) == 0 || op.Bits != nil || op.ExpectBits != nil {
+		return false
+	}
+	return op.ExpectRegisters == nil || len(op.ExpectRegisters) == len(op.Registers)
+}
+
+// matches reports whether the current values equal the expected values
+// of op; an op without expectations always matches. Caller holds m.mu.
+func (m *Memory) matches(op Op) bool {
+	switch op.Area {
+	case AreaCoils:
+		return op.ExpectBits == nil || slices.Equal(m.Coils[op.Index:op.Index+len(op.Bits)], op.ExpectBits)
+	case AreaDiscreteInputs:
+		return op.ExpectBits == nil || slices.Equal(m.DiscreteInputs[op.Index:op.Index+len(op.Bits)], op.ExpectBits)
+	case AreaHoldingRegs:
+		return op.ExpectRegisters == nil || slices.Equal(m.HoldingRegs[op.Index:op.Index+len(op.Registers)], op.ExpectRegisters)
+	case AreaInputRegs:
+		return op.ExpectRegisters == nil || slices.Equal(m.InputRegs[op.Index:op.Index+len(op.Registers)], op.ExpectRegisters)
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package core
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestApply_AllAreas(t *testing.T) {
+	mem := NewMemory(4, 4, 4, 4)
+
+	sub := mem.Subscribe(4)
+	defer sub.Close()
+
+	err := mem.From(SourceREST).Apply([]Op{
+		{Area: AreaCoils, Index: 0, Bits: []bool{true}},
+		{Area: AreaDiscreteInputs, Index: 1, Bits: []bool{true, true}},
+		{Area: AreaHoldingRegs, Index: 2, Registers: []uint16{7}},
+		{Area: AreaInputRegs, Index: 0, Registers: []uint16{1, 2}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snap := mem.Snapshot()
+	if !snap.Coils[0] || !snap.DiscreteInputs[2] || snap.HoldingRegs[2] != 7 ||
+		!slices.Equal(snap.InputRegs[:2], []uint16{1, 2}) {
+		t.Fatalf("unexpected snapshot %+v", snap)
+	}
+
+	// One generation step for the whole transaction
+	if mem.Generation() != 1 {
+		t.Fatalf("expected generation 1, got %d", mem.Generation())
+	}
+	for a := Area(0); a < areaCount; a++ {
+		if g := mem.AreaGeneration(a); g != 1 {
+			t.Fatalf("area %v: expected generation 1, got %d", a, g)
+		}
+	}
+
+	// One change per op, all at the same generation
+	for i := 0; i < 4; i++ {
+		c := <-sub.C
+		if c.Generation != 1 || c.Source != SourceREST {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	}
+}
+
+func TestApply_AllOrNothing(t *testing.T) {
+	tests := []struct {
+		name    string
+		bad     Op
+		wantErr error
+	}{
+		{"out of range", Op{Area: AreaInputRegs, Index: 3, Registers: []uint16{1, 2}}, ErrOutOfRange},
+		{"payload mismatch", Op{Area: AreaCoils, Registers: []uint16{1}}, ErrInvalidOp},
+		{"unknown area", Op{Area: areaCount, Bits: []bool{true}}, ErrInvalidOp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := NewMemory(4, 4, 4, 4)
+
+			err := mem.Apply([]Op{
+				{Area: AreaDiscreteInputs, Index: 0, Bits: []bool{true}},
+				tt.bad,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if di, _ := mem.ReadDiscreteInputs(0, 1); di[0] {
+				t.Fatal("first op applied despite rejected transaction")
+			}
+			if mem.Generation() != 0 {
+				t.Fatalf("expected generation 0, got %d", mem.Generation())
+			}
+		})
+	}
+}
+
+func TestApply_Empty(t *testing.T) {
+	mem := NewMemory(1, 1, 1, 1)
+	if err := mem.Apply(nil); !errors.Is(err, ErrInvalidOp) {
+		t.Fatalf("expected ErrInvalidOp, got %v", err)
+	}
+}
+
+func TestApply_EmptyPayload(t *testing.T) {
+	mem := NewMemory(4, 4, 4, 4)
+
+	for _, op := range []Op{
+		{Area: AreaCoils},
+		{Area: AreaDiscreteInputs, Bits: []bool{}},
+		{Area: AreaHoldingRegs},
+		{Area: AreaInputRegs, Registers: []uint16{}},
+	} {
+		if err := mem.Apply([]Op{op}); !errors.Is(err, ErrInvalidOp) {
+			t.Fatalf("%v: expected ErrInvalidOp, got %v", op.Area, err)
+		}
+	}
+	if gen := mem.Generation(); gen != 0 {
+		t.Fatalf("generation advanced to %d", gen)
+	}
+}
+
+func TestApply_RejectsGenerationRegister(t *testing.T) {
+	mem := NewMemory(0, 0, 0, 8)
+	if err := mem.SetGenerationRegister(4); err != nil {
+		t.Fatal(err)
+	}
+
+	err := mem.Apply([]Op{
+		{Area: AreaInputRegs, Index: 0, Registers: []uint16{1}},
+		{Area: AreaInputRegs, Index: 3, Registers: []uint16{1, 2}},
+	})
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Fatalf("expected ErrOutOfRange, got %v", err)
+	}
+	var opErr *OpError
+	if !errors.As(err, &opErr) || opErr.Index != 1 {
+		t.Fatalf("expected error for op 1, got %v", err)
+	}
+}
+
+func TestApply_ReadersNeverSeeTornState(t *testing.T) {
+	mem := NewMemory(0, 1, 0, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 1000; i++ {
+			_ = mem.Apply([]Op{
+				{Area: AreaDiscreteInputs, Bits: []bool{i%2 == 1}},
+				{Area: AreaInputRegs, Registers: []uint16{uint16(i % 2)}},
+			})
+		}
+	}()
+
+	for i := 0; i < 1000; i++ {
+		snap := mem.Snapshot()
+		if snap.DiscreteInputs[0] != (snap.InputRegs[0] == 1) {
+			t.Fatalf("torn state: di=%v ir=%d", snap.DiscreteInputs[0], snap.InputRegs[0])
+		}
+	}
+	wg.Wait()
+}
+
+func TestApply_TriggersGate(t *testing.T) {
+	mem := NewMemory(0, 4, 0, 0)
+	mem.SetStateSealing(true, 2)
+
+	if err := mem.Apply([]Op{{Area: AreaDiscreteInputs, Index: 2, Bits: []bool{true}}}); err != nil {
+		t.Fatal(err)
+	}
+	if mem.IsPreRun() {
+		t.Fatal("expected RUN after gate write")
+	}
+}
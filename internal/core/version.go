@@ -0,0 +1,37 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidVersion is returned by ParseVersion for a malformed token.
+var ErrInvalidVersion = errors.New("invalid version")
+
+// Version identifies a memory state across restarts: a generation of
+// the memory instance Epoch. Its string form "<epoch>-<generation>" is
+// used as REST ETag and as if_generation.
+type Version struct {
+	Epoch      string
+	Generation uint64
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%s-%d", v.Epoch, v.Generation)
+}
+
+// ParseVersion parses the string form of a Version.
+func ParseVersion(s string) (Version, error) {
+	epoch, gen, ok := strings.Cut(s, "-")
+	if !ok || epoch == "" {
+		return Version{}, ErrInvalidVersion
+	}
+
+	g, err := strconv.ParseUint(gen, 10, 64)
+	if err != nil {
+		return Version{}, ErrInvalidVersion
+	}
+	return Version{Epoch: epoch, Generation: g}, nil
+}
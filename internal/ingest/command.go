@@ -1,6 +1,8 @@
 // internal/ingest/command.go
 package ingest
 
+import "modbus-memory-appliance/internal/core"
+
 type Area string
 
 const (
@@ -13,10 +15,21 @@ const (
 // Command is the single canonical ingestion command.
 // Used by REST, MQTT, and any future transport.
 type Command struct {
-    Memory  string   `json:"memory"`
-    Area    Area     `json:"area"`
-    Address uint16   `json:"address"`
-    Bools   []int    `json:"bools,omitempty"`
-    Values  []uint16 `json:"values,omitempty"`
-}
+	Memory  string   `json:"memory"`
+	Area    Area     `json:"area"`
+	Address uint16   `json:"address"`
+	Bools   []int    `json:"bools,omitempty"`
+	Values  []uint16 `json:"values,omitempty"`
 
+	// Conditional write: the command applies only while the current
+	// values equal ExpectBools / ExpectValues (same length as the
+	// payload) and, with IfGeneration, while the area has not been
+	// written since that version ("<epoch>-<generation>", see
+	// core.Version). A failed condition returns core.ErrConflict.
+	ExpectBools  []int    `json:"expect_bools,omitempty"`
+	ExpectValues []uint16 `json:"expect_values,omitempty"`
+	IfGeneration string   `json:"if_generation,omitempty"`
+
+	// Source is set by the transport, never by the payload.
+	Source core.Source `json:"-"`
+}
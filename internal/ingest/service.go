@@ -1,6 +1,8 @@
 package ingest
 
 import (
+	"fmt"
+
 	"modbus-memory-appliance/internal/core"
 )
 
@@ -8,6 +10,9 @@ import (
 // It NEVER affects Modbus behavior.
 type Service struct {
 	memories map[string]*core.Memory
+
+	// fallback receives commands with an empty memory ("" = reject)
+	fallback string
 }
 
 // New creates a new ingest service.
@@ -17,24 +22,65 @@ func New(memories map[string]*core.Memory) *Service {
 	}
 }
 
+// SetFallbackMemory routes commands with an empty memory to memID.
+// An empty memID keeps them rejected (strict routing).
+func (s *Service) SetFallbackMemory(memID string) {
+	s.fallback = memID
+}
+
 // Ingest applies a validated command to memory.
 func (s *Service) Ingest(cmd Command) error {
-	// 1. Resolve memory
-	mem, ok := s.memories[cmd.Memory]
+	// 1. Resolve memory (empty → fallback, if enabled)
+	mem, err := s.Resolve(cmd.Memory)
+	if err != nil {
+		return err
+	}
+
+	op, err := s.op(mem, cmd)
+	if err != nil {
+		return err
+	}
+	return apply(mem.From(cmd.Source), cmd.IfGeneration, []core.Op{op})
+}
+
+// apply writes ops, conditionally when ifGeneration is set.
+func apply(w core.Writer, ifGeneration string, ops []core.Op) error {
+	if ifGeneration == "" {
+		return w.Apply(ops)
+	}
+
+	since, err := core.ParseVersion(ifGeneration)
+	if err != nil {
+		return fmt.Errorf("%w: if_generation: %v", ErrInvalidPayload, err)
+	}
+	return w.ApplyIfUnmodified(since, ops)
+}
+
+// Resolve returns the memory a command for name writes to (empty →
+// fallback).
+func (s *Service) Resolve(name string) (*core.Memory, error) {
+	if name == "" {
+		name = s.fallback
+	}
+	mem, ok := s.memories[name]
 	if !ok {
-		return ErrUnknownMemory
+		return nil, ErrUnknownMemory
 	}
+	return mem, nil
+}
 
+// op validates cmd against mem and converts it to a core write.
+func (s *Service) op(mem *core.Memory, cmd Command) (core.Op, error) {
 	// 2. Validate area
 	if !isValidArea(cmd.Area) {
-		return ErrInvalidArea
+		return core.Op{}, ErrInvalidArea
 	}
 
 	// 3. Validate payload presence (exactly one)
 	hasBools := len(cmd.Bools) > 0
 	hasValues := len(cmd.Values) > 0
 	if hasBools == hasValues {
-		return ErrInvalidPayload
+		return core.Op{}, ErrInvalidPayload
 	}
 
 	// =====================================================
@@ -45,7 +91,7 @@ func (s *Service) Ingest(cmd Command) error {
 	if !mem.IsPreRun() {
 		switch cmd.Area {
 		case Coils, HoldingRegs:
-			return ErrIngestDenied
+			return core.Op{}, ErrIngestDenied
 		}
 	}
 
@@ -56,31 +102,31 @@ func (s *Service) Ingest(cmd Command) error {
 
 	case Coils:
 		if !hasBools {
-			return ErrPayloadMismatch
+			return core.Op{}, ErrPayloadMismatch
 		}
-		return s.writeCoils(mem, cmd)
+		return bitsOp(mem, core.AreaCoils, cmd)
 
 	case DiscreteInputs:
 		if !hasBools {
-			return ErrPayloadMismatch
+			return core.Op{}, ErrPayloadMismatch
 		}
-		return s.writeDiscreteInputs(mem, cmd)
+		return bitsOp(mem, core.AreaDiscreteInputs, cmd)
 
 	case HoldingRegs:
 		if !hasValues {
-			return ErrPayloadMismatch
+			return core.Op{}, ErrPayloadMismatch
 		}
-		return s.writeHoldingRegisters(mem, cmd)
+		return regsOp(mem, core.AreaHoldingRegs, cmd)
 
 	case InputRegisters:
 		if !hasValues {
-			return ErrPayloadMismatch
+			return core.Op{}, ErrPayloadMismatch
 		}
-		return s.writeInputRegisters(mem, cmd)
+		return regsOp(mem, core.AreaInputRegs, cmd)
 	}
 
 	// unreachable
-	return ErrInvalidArea
+	return core.Op{}, ErrInvalidArea
 }
 
 // -----------------------------------------------------
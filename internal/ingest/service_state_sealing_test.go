@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"errors"
 	"testing"
 
 	"modbus-memory-appliance/internal/core"
@@ -120,3 +121,29 @@ func TestRun_AllowsDIandIRIngest(t *testing.T) {
 		t.Fatalf("expected IR ingest to succeed in RUN, got %v", err)
 	}
 }
+
+func TestRun_DeniesConditionalHoldingIngest(t *testing.T) {
+	mem := newTestMemoryWithSealing(1)
+	_ = mem.WriteDiscreteInputs(1, []bool{true}) // hit gate → RUN
+
+	svc := New(map[string]*core.Memory{
+		"plant": mem,
+	})
+
+	// A condition does not lift the seal
+	err := svc.Ingest(Command{
+		Memory:       "plant",
+		Area:         HoldingRegs,
+		Address:      0,
+		Values:       []uint16{10},
+		ExpectValues: []uint16{0},
+		IfGeneration: mem.Epoch() + "-0",
+	})
+	if !errors.Is(err, ErrIngestDenied) {
+		t.Fatalf("expected ErrIngestDenied, got %v", err)
+	}
+
+	if hr, _ := mem.ReadHoldingRegs(0, 1); hr[0] != 0 {
+		t.Fatalf("holding register written in RUN: %d", hr[0])
+	}
+}
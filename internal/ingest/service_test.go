@@ -1,6 +1,7 @@
 package ingest
 
 import (
+	"errors"
 	"testing"
 
 	"modbus-memory-appliance/internal/core"
@@ -247,3 +248,182 @@ func TestIngest_CoilsAndHoldingRegisters(t *testing.T) {
 		})
 	}
 }
+
+func TestIngest_HonorsAddressBase(t *testing.T) {
+	mem := core.NewMemory(4, 4, 4, 4)
+	mem.SetAddressBase(core.AreaInputRegs, 30000)
+
+	svc := New(map[string]*core.Memory{"test": mem})
+
+	if err := svc.Ingest(Command{
+		Memory:  "test",
+		Area:    InputRegisters,
+		Address: 30002,
+		Values:  []uint16{7, 8},
+	}); err != nil {
+		t.Fatalf("expected in-window ingest to succeed, got %v", err)
+	}
+
+	vals, _ := mem.ReadInputRegs(2, 2)
+	if vals[0] != 7 || vals[1] != 8 {
+		t.Fatalf("expected internal 2..3 written, got %v", vals)
+	}
+
+	for _, addr := range []uint16{0, 29999, 30003} {
+		err := svc.Ingest(Command{
+			Memory:  "test",
+			Area:    InputRegisters,
+			Address: addr,
+			Values:  []uint16{1, 2},
+		})
+		if !errors.Is(err, core.ErrOutOfRange) {
+			t.Fatalf("address %d: expected ErrOutOfRange, got %v", addr, err)
+		}
+	}
+}
+
+func TestIngest_EmptyMemoryFallback(t *testing.T) {
+	mem := core.NewMemory(4, 4, 4, 4)
+	svc := New(map[string]*core.Memory{"plant_a": mem})
+
+	cmd := Command{Area: InputRegisters, Address: 0, Values: []uint16{5}}
+
+	// Strict routing (default): empty memory is unknown
+	if err := svc.Ingest(cmd); !errors.Is(err, ErrUnknownMemory) {
+		t.Fatalf("expected ErrUnknownMemory, got %v", err)
+	}
+
+	svc.SetFallbackMemory("plant_a")
+	if err := svc.Ingest(cmd); err != nil {
+		t.Fatalf("expected fallback ingest to succeed, got %v", err)
+	}
+
+	vals, _ := mem.ReadInputRegs(0, 1)
+	if vals[0] != 5 {
+		t.Fatalf("expected value written to default memory, got %d", vals[0])
+	}
+
+	// An explicit unknown memory is never redirected
+	cmd.Memory = "plant_x"
+	if err := svc.Ingest(cmd); !errors.Is(err, ErrUnknownMemory) {
+		t.Fatalf("expected ErrUnknownMemory for explicit memory, got %v", err)
+	}
+}
+
+func TestIngest_ChangeCarriesSource(t *testing.T) {
+	svc := newTestService()
+	sub := svc.memories["test"].Subscribe(1)
+	defer sub.Close()
+
+	err := svc.Ingest(Command{
+		Memory: "test",
+		Area:   InputRegisters,
+		Values: []uint16{42},
+		Source: core.SourceMQTT,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if c := <-sub.C; c.Source != core.SourceMQTT || c.Registers[0] != 42 {
+		t.Fatalf("unexpected change %+v", c)
+	}
+}
+
+func TestIngest_Conditional(t *testing.T) {
+	svc := newTestService()
+	mem := svc.memories["test"]
+	_ = mem.WriteHoldingRegs(0, []uint16{10})
+
+	cmd := Command{
+		Memory:       "test",
+		Area:         HoldingRegs,
+		Values:       []uint16{11},
+		ExpectValues: []uint16{10},
+	}
+	if err := svc.Ingest(cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same command again: the value is no longer 10
+	if err := svc.Ingest(cmd); !errors.Is(err, core.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+
+	stale := core.Version{Epoch: mem.Epoch(), Generation: 0}
+	err := svc.Ingest(Command{
+		Memory:       "test",
+		Area:         HoldingRegs,
+		Values:       []uint16{12},
+		IfGeneration: stale.String(),
+	})
+	if !errors.Is(err, core.ErrConflict) {
+		t.Fatalf("expected ErrConflict for stale generation, got %v", err)
+	}
+
+	// Current generation, but of a previous boot
+	previous := core.Version{Epoch: "0000000000000000", Generation: mem.Generation()}
+	err = svc.Ingest(Command{
+		Memory:       "test",
+		Area:         HoldingRegs,
+		Values:       []uint16{12},
+		IfGeneration: previous.String(),
+	})
+	if !errors.Is(err, core.ErrConflict) {
+		t.Fatalf("expected ErrConflict for another epoch, got %v", err)
+	}
+
+	current := core.Version{Epoch: mem.Epoch(), Generation: mem.Generation()}
+	err = svc.Ingest(Command{
+		Memory:       "test",
+		Area:         HoldingRegs,
+		Values:       []uint16{12},
+		IfGeneration: current.String(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hr, _ := mem.ReadHoldingRegs(0, 1); hr[0] != 12 {
+		t.Fatalf("expected 12, got %d", hr[0])
+	}
+}
+
+func TestIngest_ConditionalPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     Command
+		wantErr error
+	}{
+		{
+			name:    "length mismatch",
+			cmd:     Command{Area: HoldingRegs, Values: []uint16{1, 2}, ExpectValues: []uint16{0}},
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "bools expected for registers",
+			cmd:     Command{Area: HoldingRegs, Values: []uint16{1}, ExpectBools: []int{0}},
+			wantErr: ErrPayloadMismatch,
+		},
+		{
+			name:    "malformed if_generation",
+			cmd:     Command{Area: HoldingRegs, Values: []uint16{1}, IfGeneration: "7"},
+			wantErr: ErrInvalidPayload,
+		},
+		{
+			name:    "invalid expected boolean",
+			cmd:     Command{Area: Coils, Bools: []int{1}, ExpectBools: []int{3}},
+			wantErr: ErrInvalidBoolean,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+			tt.cmd.Memory = "test"
+			if err := svc.Ingest(tt.cmd); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
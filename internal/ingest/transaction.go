@@ -0,0 +1,74 @@
+// internal/ingest/transaction.go
+package ingest
+
+import (
+	"errors"
+	"fmt"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// Write is one write of a Transaction.
+type Write struct {
+	Area    Area     `json:"area"`
+	Address uint16   `json:"address"`
+	Bools   []int    `json:"bools,omitempty"`
+	Values  []uint16 `json:"values,omitempty"`
+
+	// Conditional write, see Command
+	ExpectBools  []int    `json:"expect_bools,omitempty"`
+	ExpectValues []uint16 `json:"expect_values,omitempty"`
+}
+
+// Transaction is a batch of writes applied atomically to one memory:
+// Modbus readers observe all of them or none.
+type Transaction struct {
+	Memory string  `json:"memory"`
+	Writes []Write `json:"writes"`
+
+	// IfGeneration makes the whole transaction conditional, see Command.
+	IfGeneration string `json:"if_generation,omitempty"`
+
+	// Source is set by the transport, never by the payload.
+	Source core.Source `json:"-"`
+}
+
+// IngestTransaction validates every write of tx with the same rules as
+// Ingest, then applies them under one memory lock. A rejected write
+// rejects the whole transaction; its error names the write index. A
+// failed condition returns core.ErrConflict unwrapped.
+func (s *Service) IngestTransaction(tx Transaction) error {
+	mem, err := s.Resolve(tx.Memory)
+	if err != nil {
+		return err
+	}
+
+	if len(tx.Writes) == 0 {
+		return ErrInvalidPayload
+	}
+
+	ops := make([]core.Op, len(tx.Writes))
+	for i, w := range tx.Writes {
+		op, err := s.op(mem, Command{
+			Area:    w.Area,
+			Address: w.Address,
+			Bools:   w.Bools,
+			Values:  w.Values,
+
+			ExpectBools:  w.ExpectBools,
+			ExpectValues: w.ExpectValues,
+		})
+		if err != nil {
+			return fmt.Errorf("writes[%d]: %w", i, err)
+		}
+		ops[i] = op
+	}
+
+	err = apply(mem.From(tx.Source), tx.IfGeneration, ops)
+
+	var opErr *core.OpError
+	if errors.As(err, &opErr) {
+		return fmt.Errorf("writes[%d]: %w", opErr.Index, opErr.Err)
+	}
+	return err
+}
@@ -0,0 +1,150 @@
+package ingest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestIngestTransaction_AppliesAtomically(t *testing.T) {
+	svc := newTestService()
+	mem := svc.memories["test"]
+
+	err := svc.IngestTransaction(Transaction{
+		Memory: "test",
+		Writes: []Write{
+			{Area: DiscreteInputs, Address: 0, Bools: []int{1, 1}},
+			{Area: InputRegisters, Address: 4, Values: []uint16{10, 20}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	di, _ := mem.ReadDiscreteInputs(0, 2)
+	ir, _ := mem.ReadInputRegs(4, 2)
+	if !di[0] || !di[1] || ir[0] != 10 || ir[1] != 20 {
+		t.Fatalf("unexpected memory di=%v ir=%v", di, ir)
+	}
+	if mem.Generation() != 1 {
+		t.Fatalf("expected one generation step, got %d", mem.Generation())
+	}
+}
+
+func TestIngestTransaction_RejectsWhole(t *testing.T) {
+	tests := []struct {
+		name    string
+		bad     Write
+		wantErr error
+	}{
+		{"invalid area", Write{Area: "foo", Values: []uint16{1}}, ErrInvalidArea},
+		{"invalid boolean", Write{Area: DiscreteInputs, Bools: []int{2}}, ErrInvalidBoolean},
+		{"payload mismatch", Write{Area: InputRegisters, Bools: []int{1}}, ErrPayloadMismatch},
+		{"out of range", Write{Area: InputRegisters, Address: 9, Values: []uint16{1, 2}}, core.ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+
+			err := svc.IngestTransaction(Transaction{
+				Memory: "test",
+				Writes: []Write{
+					{Area: DiscreteInputs, Bools: []int{1}},
+					tt.bad,
+				},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+
+			if di, _ := svc.memories["test"].ReadDiscreteInputs(0, 1); di[0] {
+				t.Fatal("first write applied despite rejected transaction")
+			}
+		})
+	}
+}
+
+func TestIngestTransaction_ErrorNamesWrite(t *testing.T) {
+	tests := []struct {
+		name string
+		bad  Write
+	}{
+		{"payload mismatch", Write{Area: InputRegisters, Bools: []int{1}}},
+		{"out of range", Write{Area: InputRegisters, Address: 9, Values: []uint16{1, 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := newTestService()
+
+			err := svc.IngestTransaction(Transaction{
+				Memory: "test",
+				Writes: []Write{
+					{Area: InputRegisters, Values: []uint16{1}},
+					tt.bad,
+				},
+			})
+			if err == nil || !strings.HasPrefix(err.Error(), "writes[1]: ") {
+				t.Fatalf("expected error naming writes[1], got %v", err)
+			}
+		})
+	}
+}
+
+func TestIngestTransaction_Guards(t *testing.T) {
+	svc := newTestService()
+
+	if err := svc.IngestTransaction(Transaction{Memory: "test"}); !errors.Is(err, ErrInvalidPayload) {
+		t.Fatalf("expected ErrInvalidPayload for empty transaction, got %v", err)
+	}
+
+	err := svc.IngestTransaction(Transaction{
+		Memory: "nope",
+		Writes: []Write{{Area: InputRegisters, Values: []uint16{1}}},
+	})
+	if !errors.Is(err, ErrUnknownMemory) {
+		t.Fatalf("expected ErrUnknownMemory, got %v", err)
+	}
+}
+
+func TestIngestTransaction_RunDeniesHoldingRegisters(t *testing.T) {
+	mem := core.NewMemory(0, 1, 1, 1)
+	svc := New(map[string]*core.Memory{"test": mem})
+
+	err := svc.IngestTransaction(Transaction{
+		Memory: "test",
+		Writes: []Write{
+			{Area: InputRegisters, Values: []uint16{1}},
+			{Area: HoldingRegs, Values: []uint16{1}},
+		},
+	})
+	if !errors.Is(err, ErrIngestDenied) {
+		t.Fatalf("expected ErrIngestDenied, got %v", err)
+	}
+	if ir, _ := mem.ReadInputRegs(0, 1); ir[0] != 0 {
+		t.Fatal("input register written despite denied transaction")
+	}
+}
+
+func TestIngestTransaction_Conditional(t *testing.T) {
+	svc := newTestService()
+	mem := svc.memories["test"]
+	_ = mem.WriteInputRegs(0, []uint16{1})
+
+	err := svc.IngestTransaction(Transaction{
+		Memory: "test",
+		Writes: []Write{
+			{Area: DiscreteInputs, Bools: []int{1}},
+			{Area: InputRegisters, Values: []uint16{2}, ExpectValues: []uint16{5}},
+		},
+	})
+	if !errors.Is(err, core.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if di, _ := mem.ReadDiscreteInputs(0, 1); di[0] {
+		t.Fatal("transaction partially applied")
+	}
+}
@@ -3,55 +3,69 @@ package ingest
 
 import "modbus-memory-appliance/internal/core"
 
-func (s *Service) writeDiscreteInputs(mem *core.Memory, cmd Command) error {
-	bools := make([]bool, len(cmd.Bools))
+func bitsOp(mem *core.Memory, area core.Area, cmd Command) (core.Op, error) {
+	if len(cmd.ExpectValues) > 0 {
+		return core.Op{}, ErrPayloadMismatch
+	}
 
-	for i, v := range cmd.Bools {
-		switch v {
-		case 0:
-			bools[i] = false
-		case 1:
-			bools[i] = true
-		default:
-			return ErrInvalidBoolean
+	bools, err := toBools(cmd.Bools)
+	if err != nil {
+		return core.Op{}, err
+	}
+
+	op := core.Op{
+		Area:  area,
+		Index: mem.ToInternal(area, int(cmd.Address)),
+		Bits:  bools,
+	}
+
+	if len(cmd.ExpectBools) > 0 {
+		if len(cmd.ExpectBools) != len(cmd.Bools) {
+			return core.Op{}, ErrInvalidPayload
+		}
+		if op.ExpectBits, err = toBools(cmd.ExpectBools); err != nil {
+			return core.Op{}, err
 		}
 	}
 
-	return mem.WriteDiscreteInputs(
-		int(cmd.Address),
-		bools,
-	)
+	return op, nil
 }
 
-func (s *Service) writeInputRegisters(mem *core.Memory, cmd Command) error {
-	return mem.WriteInputRegs(
-		int(cmd.Address),
-		cmd.Values,
-	)
+func regsOp(mem *core.Memory, area core.Area, cmd Command) (core.Op, error) {
+	if len(cmd.ExpectBools) > 0 {
+		return core.Op{}, ErrPayloadMismatch
+	}
+
+	op := core.Op{
+		Area:      area,
+		Index:     mem.ToInternal(area, int(cmd.Address)),
+		Registers: cmd.Values,
+	}
+
+	if len(cmd.ExpectValues) > 0 {
+		if len(cmd.ExpectValues) != len(cmd.Values) {
+			return core.Op{}, ErrInvalidPayload
+		}
+		op.ExpectRegisters = cmd.ExpectValues
+	}
+
+	return op, nil
 }
-func (s *Service) writeCoils(mem *core.Memory, cmd Command) error {
-	bools := make([]bool, len(cmd.Bools))
 
-	for i, v := range cmd.Bools {
+// toBools converts numeric booleans (0 / 1).
+func toBools(in []int) ([]bool, error) {
+	bools := make([]bool, len(in))
+
+	for i, v := range in {
 		switch v {
 		case 0:
 			bools[i] = false
 		case 1:
 			bools[i] = true
 		default:
-			return ErrInvalidBoolean
+			return nil, ErrInvalidBoolean
 		}
 	}
 
-	return mem.WriteCoils(
-		int(cmd.Address),
-		bools,
-	)
-}
-
-func (s *Service) writeHoldingRegisters(mem *core.Memory, cmd Command) error {
-	return mem.WriteHoldingRegs(
-		int(cmd.Address),
-		cmd.Values,
-	)
+	return bools, nil
 }
@@ -0,0 +1,64 @@
+package modbus
+
+import (
+	"encoding/binary"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// Access is one memory range a request touches, in external addresses.
+type Access struct {
+	Area  core.Area
+	Write bool
+	Addr  int
+	Count int
+}
+
+// AccessCheck decides whether a request may touch an address range.
+// Address policy lives outside Modbus, like routing.
+type AccessCheck func(a Access) bool
+
+// requestAccesses lists the ranges a request reads and writes.
+// Caller guarantees: the PDU passed validateRequest.
+func requestAccesses(pdu PDU) []Access {
+	d := pdu.Data
+	u16 := func(i int) int { return int(binary.BigEndian.Uint16(d[i:])) }
+
+	switch pdu.Function {
+	case 0x01: // Read Coils
+		return []Access{{Area: core.AreaCoils, Addr: u16(0), Count: u16(2)}}
+	case 0x02: // Read Discrete Inputs
+		return []Access{{Area: core.AreaDiscreteInputs, Addr: u16(0), Count: u16(2)}}
+	case 0x03: // Read Holding Registers
+		return []Access{{Area: core.AreaHoldingRegs, Addr: u16(0), Count: u16(2)}}
+	case 0x04: // Read Input Registers
+		return []Access{{Area: core.AreaInputRegs, Addr: u16(0), Count: u16(2)}}
+	case 0x05: // Write Single Coil
+		return []Access{{Area: core.AreaCoils, Write: true, Addr: u16(0), Count: 1}}
+	case 0x06, 0x16: // Write Single Register, Mask Write Register
+		return []Access{{Area: core.AreaHoldingRegs, Write: true, Addr: u16(0), Count: 1}}
+	case 0x0F: // Write Multiple Coils
+		return []Access{{Area: core.AreaCoils, Write: true, Addr: u16(0), Count: u16(2)}}
+	case 0x10: // Write Multiple Registers
+		return []Access{{Area: core.AreaHoldingRegs, Write: true, Addr: u16(0), Count: u16(2)}}
+	case 0x17: // Read/Write Multiple Registers
+		return []Access{
+			{Area: core.AreaHoldingRegs, Addr: u16(0), Count: u16(2)},
+			{Area: core.AreaHoldingRegs, Write: true, Addr: u16(4), Count: u16(6)},
+		}
+	}
+	return nil
+}
+
+// allowsAccess applies the listener ACL to every range of a request.
+func (l *listener) allowsAccess(pdu PDU) bool {
+	if l.access == nil {
+		return true
+	}
+	for _, a := range requestAccesses(pdu) {
+		if !l.access(a) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestAddressACL_WriteWindow(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	resolve := func(unitID uint8, fc uint8) *core.Memory { return mem }
+
+	// Holding registers: read anywhere, write only 2..3
+	access := func(a Access) bool {
+		if a.Area != core.AreaHoldingRegs || !a.Write {
+			return true
+		}
+		return a.Addr >= 2 && a.Addr+a.Count <= 4
+	}
+	l := newListener(resolve, Policy{Access: access})
+
+	cases := []struct {
+		name string
+		pdu  PDU
+		want []byte
+	}{
+		{
+			name: "read outside write window",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x08}},
+			want: append([]byte{0x03, 0x10}, make([]byte, 16)...),
+		},
+		{
+			name: "write inside window",
+			pdu:  PDU{Function: 0x10, Data: []byte{0x00, 0x02, 0x00, 0x02, 0x04, 0x00, 0x01, 0x00, 0x02}},
+			want: []byte{0x10, 0x00, 0x02, 0x00, 0x02},
+		},
+		{
+			name: "write straddling window",
+			pdu:  PDU{Function: 0x10, Data: []byte{0x00, 0x03, 0x00, 0x02, 0x04, 0x00, 0x01, 0x00, 0x02}},
+			want: []byte{0x90, 0x02},
+		},
+		{
+			name: "mask write outside window",
+			pdu:  PDU{Function: 0x16, Data: []byte{0x00, 0x05, 0xFF, 0xFF, 0x00, 0x00}},
+			want: []byte{0x96, 0x02},
+		},
+		{
+			name: "FC23 write half denied",
+			pdu:  PDU{Function: 0x17, Data: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x06, 0x00, 0x01, 0x02, 0x00, 0x01}},
+			want: []byte{0x97, 0x02},
+		},
+	}
+
+	for _, tc := range cases {
+		if got := serveRequest(1, tc.pdu, l); !bytes.Equal(got, tc.want) {
+			t.Fatalf("%s: expected % X, got % X", tc.name, tc.want, got)
+		}
+	}
+
+	vals, _ := mem.ReadHoldingRegs(0, 8)
+	if vals[3] != 2 || vals[4] != 0 || vals[6] != 0 {
+		t.Fatalf("expected only the allowed write applied, got %v", vals)
+	}
+}
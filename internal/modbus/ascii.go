@@ -0,0 +1,84 @@
+package modbus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// asciiMaxLine is ':' + hex(address + 253 byte PDU + LRC) + CRLF.
+const asciiMaxLine = 1 + 2*(1+253+1) + 2
+
+// asciiCodec carries PDUs in ASCII frames: ':' HEX(address, PDU, LRC) CRLF.
+type asciiCodec struct {
+	br *bufio.Reader
+	w  io.Writer
+}
+
+func newASCIICodec(rw io.ReadWriter) *asciiCodec {
+	return &asciiCodec{
+		br: bufio.NewReaderSize(rw, 2*asciiMaxLine),
+		w:  rw,
+	}
+}
+
+func (c *asciiCodec) readRequest() (uint8, PDU, error) {
+	line, err := c.br.ReadSlice('\n')
+	if errors.Is(err, bufio.ErrBufferFull) {
+		// Runaway line: drop through the next line feed
+		for errors.Is(err, bufio.ErrBufferFull) {
+			_, err = c.br.ReadSlice('\n')
+		}
+		if err != nil {
+			return 0, PDU{}, err
+		}
+		return 0, PDU{}, errCorruptFrame
+	}
+	if err != nil {
+		return 0, PDU{}, err
+	}
+
+	// A ':' always starts a new frame; anything before it is noise
+	start := bytes.LastIndexByte(line, ':')
+	if start < 0 || !bytes.HasSuffix(line, []byte("\r\n")) {
+		return 0, PDU{}, errCorruptFrame
+	}
+
+	body := line[start+1 : len(line)-2]
+	if len(body) > asciiMaxLine {
+		return 0, PDU{}, errCorruptFrame
+	}
+
+	raw := make([]byte, hex.DecodedLen(len(body)))
+	if _, err := hex.Decode(raw, body); err != nil {
+		return 0, PDU{}, errCorruptFrame
+	}
+
+	// address + function + LRC at minimum
+	n := len(raw)
+	if n < 3 || lrc(raw[:n-1]) != raw[n-1] {
+		return 0, PDU{}, errCorruptFrame
+	}
+
+	return raw[0], PDU{
+		Function: raw[1],
+		Data:     raw[2 : n-1],
+	}, nil
+}
+
+func (c *asciiCodec) writeResponse(unitID uint8, resp []byte) error {
+	raw := make([]byte, 0, len(resp)+2)
+	raw = append(raw, unitID)
+	raw = append(raw, resp...)
+	raw = append(raw, lrc(raw))
+
+	out := make([]byte, 0, 1+hex.EncodedLen(len(raw))+2)
+	out = append(out, ':')
+	out = append(out, bytes.ToUpper([]byte(hex.EncodeToString(raw)))...)
+	out = append(out, '\r', '\n')
+
+	_, err := c.w.Write(out)
+	return err
+}
@@ -0,0 +1,113 @@
+package modbus
+
+import "modbus-memory-appliance/internal/core"
+
+// broadcastUnitID addresses every server on the link. Broadcast requests
+// are never answered.
+const broadcastUnitID = 0
+
+// maxUnitID is the highest individual server address.
+const maxUnitID = 247
+
+// isBroadcast reports whether the listener treats a request as broadcast.
+// Without broadcast mode, unit 0 is routed like any other unit ID.
+func (l *listener) isBroadcast(unitID uint8) bool {
+	return l.broadcast && unitID == broadcastUnitID
+}
+
+// broadcastable reports whether a function code may be broadcast.
+// Only plain writes qualify; reads have nobody to answer to.
+func broadcastable(fc uint8) bool {
+	switch fc {
+	case 0x05, 0x06, 0x0F, 0x10, 0x16:
+		return true
+	}
+	return false
+}
+
+// serveBroadcast applies a broadcast write to every memory the listener
+// can reach. It returns nil when no response must be sent; only
+// non-write function codes are answered (Illegal Function).
+//
+// The write is checked on all targets first and applied only if it is
+// valid on every one. The checks cannot go stale: memory sizes are
+// fixed and State Sealing only moves from PRE-RUN to RUN.
+//
+// Applying is best-effort per memory, not one transaction: each memory
+// is updated atomically under its own lock, one after the other, so
+// readers and other writers may act between two targets' updates.
+func serveBroadcast(pdu PDU, l *listener) []byte {
+	counters := l.counters
+
+	if !broadcastable(pdu.Function) {
+		return exception(pdu.Function, 0x01) // Illegal Function
+	}
+
+	counters.serverNoResponse.Add(1)
+
+	if validateRequest(pdu) != 0 || !l.allowsAccess(pdu) {
+		return nil
+	}
+
+	targets := l.broadcastTargets(pdu.Function)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	for _, mem := range targets {
+		// 🔒 STATE SEALING: one PRE-RUN memory blocks the whole broadcast
+		if mem.IsPreRun() || !writesInRange(pdu, l.store(mem)) {
+			return nil
+		}
+	}
+
+	counters.serverMessages.Add(1)
+	for _, mem := range targets {
+		handlePDU(pdu, l.store(mem))
+	}
+	counters.commEvents.Add(1)
+
+	return nil
+}
+
+// broadcastTargets lists the distinct memories reachable through the
+// listener's routing and policy for fc, in unit ID order.
+func (l *listener) broadcastTargets(fc uint8) []*core.Memory {
+	var out []*core.Memory
+	seen := make(map[*core.Memory]bool)
+
+	for uid := 1; uid <= maxUnitID; uid++ {
+		mem := l.resolve(uint8(uid), fc)
+		if mem == nil || seen[mem] {
+			continue
+		}
+		seen[mem] = true
+		out = append(out, mem)
+	}
+	return out
+}
+
+// writesInRange reports whether every write range of a request exists
+// in data. Memory sizes are fixed, so the answer cannot change before
+// the write is applied.
+func writesInRange(pdu PDU, data dataStore) bool {
+	for _, a := range requestAccesses(pdu) {
+		if !a.Write {
+			continue
+		}
+
+		var err error
+		switch a.Area {
+		case core.AreaCoils:
+			_, err = data.ReadCoils(a.Addr, a.Count)
+		case core.AreaHoldingRegs:
+			_, err = data.ReadHoldingRegs(a.Addr, a.Count)
+		default:
+			return false
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func newBroadcastFixture() (*listener, *core.Memory, *core.Memory) {
+	a := core.NewMemory(8, 8, 8, 8)
+	b := core.NewMemory(8, 8, 4, 8)
+	resolve := func(unitID uint8, fc uint8) *core.Memory {
+		switch unitID {
+		case 0, 1, 2:
+			return a
+		case 3:
+			return b
+		}
+		return nil
+	}
+	return newListener(resolve, Policy{Broadcast: true}), a, b
+}
+
+func TestBroadcast_WriteAllNoResponse(t *testing.T) {
+	l, a, b := newBroadcastFixture()
+
+	resp := serveRequest(0, PDU{Function: 0x06, Data: []byte{0x00, 0x02, 0x12, 0x34}}, l)
+	if resp != nil {
+		t.Fatalf("expected no response, got % X", resp)
+	}
+	if a.HoldingRegs[2] != 0x1234 || b.HoldingRegs[2] != 0x1234 {
+		t.Fatalf("broadcast not applied: %04X %04X", a.HoldingRegs[2], b.HoldingRegs[2])
+	}
+
+	if got := l.counters.serverNoResponse.Load(); got != 1 {
+		t.Fatalf("expected no-response count 1, got %d", got)
+	}
+}
+
+func TestBroadcast_AllOrNothing(t *testing.T) {
+	l, a, b := newBroadcastFixture()
+
+	// HR 6 exists in a but not in b
+	serveRequest(0, PDU{Function: 0x06, Data: []byte{0x00, 0x06, 0x12, 0x34}}, l)
+	if a.HoldingRegs[6] != 0 {
+		t.Fatal("broadcast applied although one target rejects it")
+	}
+
+	// A sealed target blocks the broadcast too
+	b.SetStateSealing(true, 0)
+	serveRequest(0, PDU{Function: 0x05, Data: []byte{0x00, 0x01, 0xFF, 0x00}}, l)
+	if a.Coils[1] {
+		t.Fatal("broadcast applied although one target is Pre-Run")
+	}
+}
+
+func TestBroadcast_ReadIsIllegal(t *testing.T) {
+	l, _, _ := newBroadcastFixture()
+
+	resp := serveRequest(0, PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}}, l)
+	if want := []byte{0x83, 0x01}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+
+	// Without broadcast mode unit 0 routes normally
+	l.broadcast = false
+	resp = serveRequest(0, PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}}, l)
+	if want := []byte{0x03, 0x02, 0x00, 0x00}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
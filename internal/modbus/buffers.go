@@ -0,0 +1,20 @@
+package modbus
+
+import "sync"
+
+// framePool recycles MBAP frame buffers. Each holds one complete ADU
+// (maxMBAPADU bytes) and is returned once the frame has been handled.
+var framePool = sync.Pool{
+	New: func() any {
+		b := make([]byte, maxMBAPADU)
+		return &b
+	},
+}
+
+func getFrame() *[]byte {
+	return framePool.Get().(*[]byte)
+}
+
+func putFrame(b *[]byte) {
+	framePool.Put(b)
+}
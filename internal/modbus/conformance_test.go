@@ -0,0 +1,160 @@
+package modbus
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// Golden-frame conformance suite.
+// Every case is a full MBAP request and the exact response expected on the
+// wire (or a closed connection). Each case runs against a fresh memory:
+//
+//	coils      [1 0 1 0 ...]
+//	discrete   [1 0 0 1 0 ...]
+//	holding    [000A 000B 000C 000D 0 ...]
+//	input      [1111 2222 0 ...]
+//
+// Unit 1 is mapped, every other unit is unmapped.
+
+func newConformanceMemory() *core.Memory {
+	mem := core.NewMemory(16, 16, 16, 16)
+	_ = mem.WriteCoils(0, []bool{true, false, true})
+	_ = mem.WriteDiscreteInputs(0, []bool{true, false, false, true})
+	_ = mem.WriteHoldingRegs(0, []uint16{0x000A, 0x000B, 0x000C, 0x000D})
+	_ = mem.WriteInputRegs(0, []uint16{0x1111, 0x2222})
+	return mem
+}
+
+// adu builds an MBAP frame (transaction 0x0001, unit 1) around a PDU.
+func adu(unitID uint8, pdu ...byte) []byte {
+	out := make([]byte, 7, 7+len(pdu))
+	binary.BigEndian.PutUint16(out[0:2], 0x0001)
+	binary.BigEndian.PutUint16(out[4:6], uint16(len(pdu)+1))
+	out[6] = unitID
+	return append(out, pdu...)
+}
+
+// exchange sends one raw frame and returns the response frame,
+// or nil when the server closed the connection instead.
+func exchange(t *testing.T, req []byte) []byte {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	client, server := net.Pipe()
+	defer client.Close()
+	go handleConn(server, FramingMBAP, newListener(unitOneResolver(mem), Policy{}))
+
+	_ = client.SetDeadline(time.Now().Add(time.Second))
+	go func() { _, _ = client.Write(req) }()
+
+	hdr := make([]byte, 7)
+	if _, err := io.ReadFull(client, hdr); err != nil {
+		return nil
+	}
+	rest := make([]byte, int(binary.BigEndian.Uint16(hdr[4:6]))-1)
+	if _, err := io.ReadFull(client, rest); err != nil {
+		t.Fatalf("short response: %v", err)
+	}
+	return append(hdr, rest...)
+}
+
+func TestConformance_GoldenFrames(t *testing.T) {
+	tests := []struct {
+		name string
+		req  []byte
+		resp []byte
+	}{
+		// FC 01 Read Coils
+		{"fc01 read", adu(1, 0x01, 0x00, 0x00, 0x00, 0x03), adu(1, 0x01, 0x01, 0x05)},
+		{"fc01 quantity 0", adu(1, 0x01, 0x00, 0x00, 0x00, 0x00), adu(1, 0x81, 0x03)},
+		{"fc01 quantity 2001", adu(1, 0x01, 0x00, 0x00, 0x07, 0xD1), adu(1, 0x81, 0x03)},
+		{"fc01 out of range", adu(1, 0x01, 0x00, 0x0F, 0x00, 0x02), adu(1, 0x81, 0x02)},
+
+		// FC 02 Read Discrete Inputs
+		{"fc02 read", adu(1, 0x02, 0x00, 0x00, 0x00, 0x04), adu(1, 0x02, 0x01, 0x09)},
+		{"fc02 short pdu", adu(1, 0x02, 0x00, 0x00), adu(1, 0x82, 0x03)},
+
+		// FC 03 Read Holding Registers
+		{"fc03 read", adu(1, 0x03, 0x00, 0x00, 0x00, 0x02), adu(1, 0x03, 0x04, 0x00, 0x0A, 0x00, 0x0B)},
+		{"fc03 quantity 126", adu(1, 0x03, 0x00, 0x00, 0x00, 0x7E), adu(1, 0x83, 0x03)},
+		{"fc03 address past end", adu(1, 0x03, 0x00, 0x10, 0x00, 0x01), adu(1, 0x83, 0x02)},
+		{"fc03 address overflow", adu(1, 0x03, 0xFF, 0xFF, 0x00, 0x7D), adu(1, 0x83, 0x02)},
+		{"fc03 trailing bytes", adu(1, 0x03, 0x00, 0x00, 0x00, 0x01, 0x00), adu(1, 0x83, 0x03)},
+
+		// FC 04 Read Input Registers
+		{"fc04 read", adu(1, 0x04, 0x00, 0x00, 0x00, 0x02), adu(1, 0x04, 0x04, 0x11, 0x11, 0x22, 0x22)},
+		{"fc04 empty pdu", adu(1, 0x04), adu(1, 0x84, 0x03)},
+
+		// FC 05 Write Single Coil
+		{"fc05 on", adu(1, 0x05, 0x00, 0x01, 0xFF, 0x00), adu(1, 0x05, 0x00, 0x01, 0xFF, 0x00)},
+		{"fc05 bad value", adu(1, 0x05, 0x00, 0x01, 0x12, 0x34), adu(1, 0x85, 0x03)},
+		{"fc05 out of range", adu(1, 0x05, 0x00, 0x10, 0xFF, 0x00), adu(1, 0x85, 0x02)},
+
+		// FC 06 Write Single Register
+		{"fc06 write", adu(1, 0x06, 0x00, 0x02, 0x12, 0x34), adu(1, 0x06, 0x00, 0x02, 0x12, 0x34)},
+		{"fc06 out of range", adu(1, 0x06, 0x00, 0x10, 0x00, 0x01), adu(1, 0x86, 0x02)},
+
+		// FC 15 Write Multiple Coils
+		{"fc15 write", adu(1, 0x0F, 0x00, 0x00, 0x00, 0x0A, 0x02, 0xCD, 0x01), adu(1, 0x0F, 0x00, 0x00, 0x00, 0x0A)},
+		{"fc15 byte count mismatch", adu(1, 0x0F, 0x00, 0x00, 0x00, 0x0A, 0x01, 0xCD), adu(1, 0x8F, 0x03)},
+		{"fc15 quantity 1969", adu(1, 0x0F, 0x00, 0x00, 0x07, 0xB1, 0x00), adu(1, 0x8F, 0x03)},
+
+		// FC 16 Write Multiple Registers
+		{"fc16 write", adu(1, 0x10, 0x00, 0x01, 0x00, 0x02, 0x04, 0x00, 0x0A, 0x01, 0x02), adu(1, 0x10, 0x00, 0x01, 0x00, 0x02)},
+		{"fc16 quantity 124", adu(1, 0x10, 0x00, 0x00, 0x00, 0x7C, 0xF8), adu(1, 0x90, 0x03)},
+		{"fc16 missing values", adu(1, 0x10, 0x00, 0x00, 0x00, 0x02, 0x04, 0x00, 0x01), adu(1, 0x90, 0x03)},
+		{"fc16 out of range", adu(1, 0x10, 0x00, 0x0F, 0x00, 0x02, 0x04, 0x00, 0x01, 0x00, 0x02), adu(1, 0x90, 0x02)},
+
+		// FC 22 Mask Write Register
+		{"fc22 mask", adu(1, 0x16, 0x00, 0x00, 0x00, 0xF2, 0x00, 0x25), adu(1, 0x16, 0x00, 0x00, 0x00, 0xF2, 0x00, 0x25)},
+		{"fc22 short pdu", adu(1, 0x16, 0x00, 0x00, 0x00, 0xF2), adu(1, 0x96, 0x03)},
+
+		// FC 23 Read/Write Multiple Registers
+		{"fc23 read write", adu(1, 0x17, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01, 0x00, 0x01, 0x02, 0x00, 0xFF), adu(1, 0x17, 0x04, 0x00, 0x0A, 0x00, 0xFF)},
+		{"fc23 write quantity 122", adu(1, 0x17, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x7A, 0xF4), adu(1, 0x97, 0x03)},
+		{"fc23 read quantity 0", adu(1, 0x17, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0x00, 0x01), adu(1, 0x97, 0x03)},
+
+		// FC 08 Diagnostics
+		{"fc08 return query data", adu(1, 0x08, 0x00, 0x00, 0xA5, 0x37), adu(1, 0x08, 0x00, 0x00, 0xA5, 0x37)},
+		{"fc08 bus message count", adu(1, 0x08, 0x00, 0x0B, 0x00, 0x00), adu(1, 0x08, 0x00, 0x0B, 0x00, 0x01)},
+		{"fc08 unsupported sub-function", adu(1, 0x08, 0x00, 0x04, 0x00, 0x00), adu(1, 0x88, 0x01)},
+		{"fc08 counter with data", adu(1, 0x08, 0x00, 0x0B, 0x00, 0x01), adu(1, 0x88, 0x03)},
+
+		// FC 11 Get Comm Event Counter
+		{"fc11", adu(1, 0x0B), adu(1, 0x0B, 0x00, 0x00, 0x00, 0x00)},
+
+		// FC 17 Report Server ID
+		{"fc17", adu(1, 0x11), adu(1, 0x11, 0x05, 0x01, 0xFF, 'M', 'M', 'A')},
+
+		// FC 43 Encapsulated Interface Transport
+		{"fc43 identity not configured", adu(1, 0x2B, 0x0E, 0x01, 0x00), adu(1, 0xAB, 0x01)},
+		{"fc43 unsupported mei", adu(1, 0x2B, 0x0D, 0x00), adu(1, 0xAB, 0x01)},
+
+		// Routing and unknown functions
+		{"unknown function", adu(1, 0x42, 0x00), adu(1, 0xC2, 0x01)},
+		{"unmapped unit", adu(9, 0x03, 0x00, 0x00, 0x00, 0x01), adu(9, 0x83, 0x02)},
+		{"unmapped unit unknown function", adu(9, 0x42, 0x00), adu(9, 0xC2, 0x01)},
+		{"unmapped unit bad quantity", adu(9, 0x03, 0x00, 0x00, 0x00, 0x00), adu(9, 0x83, 0x03)},
+
+		// Framing errors close the connection
+		{"mbap length 0", []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x01}, nil},
+		{"mbap length 1", []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x01}, nil},
+		{"mbap length 255", append([]byte{0x00, 0x01, 0x00, 0x00, 0x00, 0xFF, 0x01}, make([]byte, 254)...), nil},
+		{"protocol id 1", []byte{0x00, 0x01, 0x00, 0x01, 0x00, 0x06, 0x01, 0x03, 0x00, 0x00, 0x00, 0x01}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exchange(t, tt.req)
+			if !bytes.Equal(got, tt.resp) {
+				t.Fatalf("request  % X\nexpected % X\ngot      % X", tt.req, tt.resp, got)
+			}
+		})
+	}
+}
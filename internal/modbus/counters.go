@@ -0,0 +1,45 @@
+package modbus
+
+import "sync/atomic"
+
+// commCounters holds the communication counters of one Modbus listener.
+// Counters are 16-bit on the wire and wrap around.
+type commCounters struct {
+	busMessages      atomic.Uint32
+	busCommErrors    atomic.Uint32
+	busExceptions    atomic.Uint32
+	serverMessages   atomic.Uint32
+	serverNoResponse atomic.Uint32
+	commEvents       atomic.Uint32
+}
+
+func newCommCounters() *commCounters {
+	return &commCounters{}
+}
+
+// clear resets all counters (FC 08 sub-functions 0x01 and 0x0A).
+func (c *commCounters) clear() {
+	c.busMessages.Store(0)
+	c.busCommErrors.Store(0)
+	c.busExceptions.Store(0)
+	c.serverMessages.Store(0)
+	c.serverNoResponse.Store(0)
+	c.commEvents.Store(0)
+}
+
+// recordResponse classifies a response PDU after it was produced.
+// The comm event counter counts successful completions only, and
+// never the Get Comm Event Counter request itself.
+func (c *commCounters) recordResponse(fc uint8, resp []byte) {
+	if len(resp) > 0 && resp[0]&0x80 != 0 {
+		c.busExceptions.Add(1)
+		return
+	}
+	if fc != 0x0B {
+		c.commEvents.Add(1)
+	}
+}
+
+func load16(v *atomic.Uint32) uint16 {
+	return uint16(v.Load())
+}
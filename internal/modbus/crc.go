@@ -0,0 +1,36 @@
+package modbus
+
+// crcTable is the Modbus CRC16 table (reflected polynomial 0xA001).
+var crcTable = func() [256]uint16 {
+	var t [256]uint16
+	for i := range t {
+		crc := uint16(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 != 0 {
+				crc = (crc >> 1) ^ 0xA001
+			} else {
+				crc >>= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}()
+
+// crc16 computes the Modbus RTU CRC. It is sent low byte first.
+func crc16(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc = (crc >> 8) ^ crcTable[uint8(crc)^b]
+	}
+	return crc
+}
+
+// lrc computes the Modbus ASCII longitudinal redundancy check.
+func lrc(data []byte) uint8 {
+	var sum uint8
+	for _, b := range data {
+		sum += b
+	}
+	return -sum
+}
@@ -0,0 +1,132 @@
+package modbus
+
+import "sort"
+
+// MEI types carried by FC 0x2B.
+const meiReadDeviceID = 0x0E
+
+// Read Device ID codes.
+const (
+	readDevIDBasic      = 0x01
+	readDevIDRegular    = 0x02
+	readDevIDExtended   = 0x03
+	readDevIDIndividual = 0x04
+)
+
+// Conformity levels (0x80 = individual access supported).
+const (
+	conformityBasic    = 0x81
+	conformityRegular  = 0x82
+	conformityExtended = 0x83
+)
+
+// maxPDUSize is the largest Modbus PDU (function code included).
+const maxPDUSize = 253
+
+// handleEncapsulated serves FC 0x2B. Only MEI 0x0E is supported.
+func handleEncapsulated(pdu PDU, data dataStore) []byte {
+	if len(pdu.Data) < 1 || pdu.Data[0] != meiReadDeviceID {
+		return exception(pdu.Function, 0x01)
+	}
+	return handleReadDeviceID(pdu, data)
+}
+
+// handleReadDeviceID serves Read Device Identification (FC 0x2B / MEI 0x0E)
+// from the identity objects attached to the resolved memory.
+func handleReadDeviceID(pdu PDU, data dataStore) []byte {
+	if len(pdu.Data) != 3 {
+		return exception(pdu.Function, 0x03)
+	}
+
+	code := pdu.Data[1]
+	objectID := pdu.Data[2]
+
+	identity := data.DeviceIdentity()
+	if identity == nil {
+		// No identity configured for this memory
+		return exception(pdu.Function, 0x01)
+	}
+
+	var ids []uint8
+
+	switch code {
+	case readDevIDBasic, readDevIDRegular, readDevIDExtended:
+		last := categoryLastID(code)
+
+		// Unknown start object: restart at the beginning of the stream
+		if _, ok := identity[objectID]; !ok || objectID > last {
+			objectID = 0x00
+		}
+
+		for id := range identity {
+			if id >= objectID && id <= last {
+				ids = append(ids, id)
+			}
+		}
+
+	case readDevIDIndividual:
+		if _, ok := identity[objectID]; !ok {
+			return exception(pdu.Function, 0x02)
+		}
+		ids = []uint8{objectID}
+
+	default:
+		return exception(pdu.Function, 0x03)
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	out := []byte{
+		pdu.Function,
+		meiReadDeviceID,
+		code,
+		conformityLevel(identity),
+		0x00, // more follows
+		0x00, // next object id
+		0x00, // number of objects
+	}
+
+	count := 0
+	for i, id := range ids {
+		v := identity[id]
+		if len(out)+2+len(v) > maxPDUSize {
+			// Does not fit: client continues from this object
+			out[4] = 0xFF
+			out[5] = ids[i]
+			break
+		}
+
+		out = append(out, id, uint8(len(v)))
+		out = append(out, v...)
+		count++
+	}
+	out[6] = uint8(count)
+
+	return out
+}
+
+// categoryLastID returns the highest object ID included in a stream request.
+// Each stream level includes all lower levels.
+func categoryLastID(code uint8) uint8 {
+	switch code {
+	case readDevIDBasic:
+		return 0x02
+	case readDevIDRegular:
+		return 0x7F
+	default:
+		return 0xFF
+	}
+}
+
+func conformityLevel(identity map[uint8]string) uint8 {
+	level := uint8(conformityBasic)
+	for id := range identity {
+		switch {
+		case id >= 0x80:
+			return conformityExtended
+		case id >= 0x03:
+			level = conformityRegular
+		}
+	}
+	return level
+}
@@ -0,0 +1,90 @@
+package modbus
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func newIdentityMemory(objects map[uint8]string) *core.Memory {
+	mem := core.NewMemory(8, 8, 8, 8)
+	mem.SetDeviceIdentity(objects)
+	return mem
+}
+
+func TestReadDeviceID_Basic(t *testing.T) {
+	mem := newIdentityMemory(map[uint8]string{
+		0x00: "ACME",
+		0x01: "P1",
+		0x02: "1.0",
+		0x04: "Pump",
+	})
+
+	resp := handlePDU(PDU{Function: 0x2B, Data: []byte{0x0E, 0x01, 0x00}}, memoryStore{mem: mem})
+
+	want := []byte{
+		0x2B, 0x0E, 0x01, 0x82, 0x00, 0x00, 0x03,
+		0x00, 0x04, 'A', 'C', 'M', 'E',
+		0x01, 0x02, 'P', '1',
+		0x02, 0x03, '1', '.', '0',
+	}
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+func TestReadDeviceID_Individual(t *testing.T) {
+	mem := newIdentityMemory(map[uint8]string{
+		0x00: "ACME",
+		0x01: "P1",
+		0x02: "1.0",
+		0x81: "line-3",
+	})
+
+	resp := handlePDU(PDU{Function: 0x2B, Data: []byte{0x0E, 0x04, 0x81}}, memoryStore{mem: mem})
+
+	want := []byte{
+		0x2B, 0x0E, 0x04, 0x83, 0x00, 0x00, 0x01,
+		0x81, 0x06, 'l', 'i', 'n', 'e', '-', '3',
+	}
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+
+	resp = handlePDU(PDU{Function: 0x2B, Data: []byte{0x0E, 0x04, 0x05}}, memoryStore{mem: mem})
+	if !bytes.Equal(resp, []byte{0xAB, 0x02}) {
+		t.Fatalf("expected exception 02 for missing object, got % X", resp)
+	}
+}
+
+func TestReadDeviceID_MoreFollows(t *testing.T) {
+	long := strings.Repeat("x", 200)
+	mem := newIdentityMemory(map[uint8]string{
+		0x00: "ACME",
+		0x01: "P1",
+		0x02: "1.0",
+		0x80: long,
+		0x81: long,
+	})
+
+	resp := handlePDU(PDU{Function: 0x2B, Data: []byte{0x0E, 0x03, 0x00}}, memoryStore{mem: mem})
+	if resp[4] != 0xFF || resp[5] != 0x81 || resp[6] != 4 {
+		t.Fatalf("expected more follows from 0x81 after 4 objects, got % X", resp[:7])
+	}
+
+	resp = handlePDU(PDU{Function: 0x2B, Data: []byte{0x0E, 0x03, 0x81}}, memoryStore{mem: mem})
+	if resp[4] != 0x00 || resp[6] != 1 || resp[7] != 0x81 {
+		t.Fatalf("expected final page with object 0x81, got % X", resp[:8])
+	}
+}
+
+func TestReadDeviceID_NotConfigured(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+
+	resp := handlePDU(PDU{Function: 0x2B, Data: []byte{0x0E, 0x01, 0x00}}, memoryStore{mem: mem})
+	if !bytes.Equal(resp, []byte{0xAB, 0x01}) {
+		t.Fatalf("expected exception 01, got % X", resp)
+	}
+}
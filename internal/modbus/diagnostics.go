@@ -0,0 +1,110 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"sync/atomic"
+)
+
+// FC 08 sub-functions.
+const (
+	diagReturnQueryData        = 0x0000
+	diagRestartCommOption      = 0x0001
+	diagClearCounters          = 0x000A
+	diagBusMessageCount        = 0x000B
+	diagBusCommErrorCount      = 0x000C
+	diagBusExceptionErrorCount = 0x000D
+	diagServerMessageCount     = 0x000E
+	diagServerNoResponseCount  = 0x000F
+)
+
+// Run indicator status reported by FC 0x11.
+const runIndicatorOn = 0xFF
+
+// serverIDData is the device specific data returned by Report Server ID.
+var serverIDData = []byte("MMA")
+
+// handleDiagnostics serves FC 08 from the listener counters.
+func handleDiagnostics(pdu PDU, counters *commCounters) []byte {
+	if len(pdu.Data) < 4 {
+		return exception(pdu.Function, 0x03)
+	}
+
+	sub := binary.BigEndian.Uint16(pdu.Data[0:2])
+	data := binary.BigEndian.Uint16(pdu.Data[2:4])
+
+	switch sub {
+	case diagReturnQueryData:
+		// Echo request unchanged
+		return append([]byte{pdu.Function}, pdu.Data...)
+
+	case diagRestartCommOption:
+		if len(pdu.Data) != 4 || (data != 0x0000 && data != 0xFF00) {
+			return exception(pdu.Function, 0x03)
+		}
+		// TCP has no port to restart: only the counters are reset.
+		counters.clear()
+		return append([]byte{pdu.Function}, pdu.Data...)
+
+	case diagClearCounters:
+		if len(pdu.Data) != 4 || data != 0x0000 {
+			return exception(pdu.Function, 0x03)
+		}
+		counters.clear()
+		return append([]byte{pdu.Function}, pdu.Data...)
+
+	case diagBusMessageCount:
+		return counterResponse(pdu, &counters.busMessages)
+	case diagBusCommErrorCount:
+		return counterResponse(pdu, &counters.busCommErrors)
+	case diagBusExceptionErrorCount:
+		return counterResponse(pdu, &counters.busExceptions)
+	case diagServerMessageCount:
+		return counterResponse(pdu, &counters.serverMessages)
+	case diagServerNoResponseCount:
+		return counterResponse(pdu, &counters.serverNoResponse)
+
+	default:
+		return exception(pdu.Function, 0x01)
+	}
+}
+
+// counterResponse answers a "return counter" sub-function.
+// The request data field must be 0x0000.
+func counterResponse(pdu PDU, counter *atomic.Uint32) []byte {
+	if len(pdu.Data) != 4 || binary.BigEndian.Uint16(pdu.Data[2:4]) != 0x0000 {
+		return exception(pdu.Function, 0x03)
+	}
+
+	out := make([]byte, 5)
+	out[0] = pdu.Function
+	copy(out[1:3], pdu.Data[0:2])
+	binary.BigEndian.PutUint16(out[3:5], load16(counter))
+	return out
+}
+
+// handleGetCommEventCounter serves FC 0x0B.
+// There are no long-running program commands, so the status word is
+// never busy.
+func handleGetCommEventCounter(pdu PDU, counters *commCounters) []byte {
+	if len(pdu.Data) != 0 {
+		return exception(pdu.Function, 0x03)
+	}
+
+	out := make([]byte, 5)
+	out[0] = pdu.Function
+	binary.BigEndian.PutUint16(out[1:3], 0x0000)
+	binary.BigEndian.PutUint16(out[3:5], load16(&counters.commEvents))
+	return out
+}
+
+// handleReportServerID serves FC 0x11.
+// The server ID is the unit ID the request was routed by. Only RUN-state
+// memories reach this point, so the run indicator is always ON.
+func handleReportServerID(pdu PDU, unitID uint8) []byte {
+	if len(pdu.Data) != 0 {
+		return exception(pdu.Function, 0x03)
+	}
+
+	out := []byte{pdu.Function, uint8(2 + len(serverIDData)), unitID, runIndicatorOn}
+	return append(out, serverIDData...)
+}
@@ -0,0 +1,85 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func newDiagFixture() (*listener, *commCounters) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	l := newListener(unitOneResolver(mem), Policy{})
+	return l, l.counters
+}
+
+func TestDiagnostics_ReturnQueryData(t *testing.T) {
+	l, _ := newDiagFixture()
+
+	req := PDU{Function: 0x08, Data: []byte{0x00, 0x00, 0xA5, 0x37}}
+	resp := serveRequest(1, req, l)
+
+	want := []byte{0x08, 0x00, 0x00, 0xA5, 0x37}
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected echo % X, got % X", want, resp)
+	}
+}
+
+func TestDiagnostics_Counters(t *testing.T) {
+	l, _ := newDiagFixture()
+
+	// one good read, one exception (unknown unit), one illegal function
+	serveRequest(1, PDU{Function: 0x03, Data: []byte{0, 0, 0, 1}}, l)
+	serveRequest(9, PDU{Function: 0x03, Data: []byte{0, 0, 0, 1}}, l)
+	serveRequest(1, PDU{Function: 0x42}, l)
+
+	tests := []struct {
+		name string
+		sub  byte
+		want uint16
+	}{
+		{"bus messages", 0x0B, 4},    // includes this request
+		{"bus exceptions", 0x0D, 2},  // unknown unit + illegal function
+		{"server messages", 0x0E, 5}, // routed requests, unknown unit excluded
+		{"no response", 0x0F, 0},
+	}
+
+	for _, tt := range tests {
+		resp := serveRequest(1, PDU{Function: 0x08, Data: []byte{0x00, tt.sub, 0, 0}}, l)
+		got := uint16(resp[3])<<8 | uint16(resp[4])
+		if resp[0] != 0x08 || got != tt.want {
+			t.Fatalf("%s: expected %d, got % X", tt.name, tt.want, resp)
+		}
+	}
+
+	// clear counters
+	serveRequest(1, PDU{Function: 0x08, Data: []byte{0x00, 0x0A, 0, 0}}, l)
+	resp := serveRequest(1, PDU{Function: 0x08, Data: []byte{0x00, 0x0D, 0, 0}}, l)
+	if resp[3] != 0 || resp[4] != 0 {
+		t.Fatalf("expected cleared exception counter, got % X", resp)
+	}
+}
+
+func TestGetCommEventCounter(t *testing.T) {
+	l, _ := newDiagFixture()
+
+	serveRequest(1, PDU{Function: 0x03, Data: []byte{0, 0, 0, 1}}, l)
+	serveRequest(1, PDU{Function: 0x06, Data: []byte{0, 0, 0, 7}}, l)
+	serveRequest(1, PDU{Function: 0x03, Data: []byte{0, 99, 0, 1}}, l) // exception
+
+	resp := serveRequest(1, PDU{Function: 0x0B}, l)
+	want := []byte{0x0B, 0x00, 0x00, 0x00, 0x02}
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+func TestReportServerID(t *testing.T) {
+	l, _ := newDiagFixture()
+
+	resp := serveRequest(1, PDU{Function: 0x11}, l)
+	want := []byte{0x11, 0x05, 0x01, 0xFF, 'M', 'M', 'A'}
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
@@ -0,0 +1,44 @@
+package modbus
+
+import (
+	"errors"
+	"net"
+)
+
+// Framing selects how request PDUs are carried on a connection.
+type Framing string
+
+const (
+	FramingMBAP  Framing = "mbap"  // Modbus TCP (default)
+	FramingRTU   Framing = "rtu"   // RTU frames with CRC16, e.g. transparent serial gateways
+	FramingASCII Framing = "ascii" // ASCII frames with LRC
+)
+
+var (
+	// errInvalidFrame is a framing error the connection cannot recover from.
+	errInvalidFrame = errors.New("modbus: invalid frame")
+
+	// errCorruptFrame is a dropped frame (CRC/LRC, truncation); the
+	// stream was resynchronised and the next frame can be read.
+	errCorruptFrame = errors.New("modbus: corrupt frame dropped")
+)
+
+// frameCodec reads requests from and writes responses to one connection.
+type frameCodec interface {
+	// readRequest returns the unit ID and PDU of the next request.
+	readRequest() (uint8, PDU, error)
+
+	// writeResponse sends the response PDU for the last request read.
+	writeResponse(unitID uint8, resp []byte) error
+}
+
+func newFrameCodec(conn net.Conn, framing Framing) frameCodec {
+	switch framing {
+	case FramingRTU:
+		return newRTUCodec(conn, conn, rtuTCPFrameGap)
+	case FramingASCII:
+		return newASCIICodec(conn)
+	default:
+		return newMBAPCodec(conn)
+	}
+}
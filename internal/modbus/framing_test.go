@@ -0,0 +1,131 @@
+package modbus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func startFramedConn(t *testing.T, framing Framing) (net.Conn, *commCounters) {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	client, server := net.Pipe()
+	l := newListener(unitOneResolver(mem), Policy{})
+	go handleConn(server, framing, l)
+	counters := l.counters
+
+	t.Cleanup(func() { client.Close() })
+	_ = client.SetDeadline(time.Now().Add(2 * time.Second))
+	return client, counters
+}
+
+func withCRC(frame ...byte) []byte {
+	return binary.LittleEndian.AppendUint16(frame, crc16(frame))
+}
+
+func TestCRC16_KnownVector(t *testing.T) {
+	// 01 03 00 00 00 01 -> CRC 84 0A on the wire
+	got := withCRC(0x01, 0x03, 0x00, 0x00, 0x00, 0x01)
+	if !bytes.Equal(got[6:], []byte{0x84, 0x0A}) {
+		t.Fatalf("expected CRC 84 0A, got % X", got[6:])
+	}
+}
+
+func TestRTUFraming_ReadHoldingRegisters(t *testing.T) {
+	client, _ := startFramedConn(t, FramingRTU)
+
+	go func() { _, _ = client.Write(withCRC(0x01, 0x03, 0x00, 0x00, 0x00, 0x02)) }()
+
+	want := withCRC(0x01, 0x03, 0x04, 0x00, 0x0A, 0x00, 0x0B)
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(client, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestRTUFraming_SplitWriteMultiple(t *testing.T) {
+	client, _ := startFramedConn(t, FramingRTU)
+
+	req := withCRC(0x01, 0x10, 0x00, 0x01, 0x00, 0x02, 0x04, 0x00, 0x0A, 0x01, 0x02)
+	go func() {
+		_, _ = client.Write(req[:5])
+		time.Sleep(10 * time.Millisecond)
+		_, _ = client.Write(req[5:])
+	}()
+
+	want := withCRC(0x01, 0x10, 0x00, 0x01, 0x00, 0x02)
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(client, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestRTUFraming_BadCRCIsDroppedAndCounted(t *testing.T) {
+	client, counters := startFramedConn(t, FramingRTU)
+
+	bad := withCRC(0x01, 0x03, 0x00, 0x00, 0x00, 0x01)
+	bad[len(bad)-1] ^= 0xFF
+
+	go func() {
+		_, _ = client.Write(bad)
+		time.Sleep(2 * rtuTCPFrameGap) // inter-frame silence
+		_, _ = client.Write(withCRC(0x01, 0x03, 0x00, 0x01, 0x00, 0x01))
+	}()
+
+	want := withCRC(0x01, 0x03, 0x02, 0x00, 0x0B)
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(client, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+	if n := counters.busCommErrors.Load(); n != 1 {
+		t.Fatalf("expected 1 bus comm error, got %d", n)
+	}
+}
+
+func TestASCIIFraming_ReadHoldingRegisters(t *testing.T) {
+	client, _ := startFramedConn(t, FramingASCII)
+
+	go func() { _, _ = client.Write([]byte(":010300000002FA\r\n")) }()
+
+	line, err := bufio.NewReader(client).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != ":010304000A000BE3\r\n" {
+		t.Fatalf("unexpected response %q", line)
+	}
+}
+
+func TestASCIIFraming_BadLRCIsDropped(t *testing.T) {
+	client, counters := startFramedConn(t, FramingASCII)
+
+	go func() {
+		_, _ = client.Write([]byte(":010300000002FB\r\n"))
+		_, _ = client.Write([]byte(":010300000001FB\r\n"))
+	}()
+
+	line, err := bufio.NewReader(client).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != ":010302000AF0\r\n" {
+		t.Fatalf("unexpected response %q", line)
+	}
+	if n := counters.busCommErrors.Load(); n != 1 {
+		t.Fatalf("expected 1 bus comm error, got %d", n)
+	}
+}
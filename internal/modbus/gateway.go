@@ -0,0 +1,36 @@
+package modbus
+
+import "errors"
+
+// UpstreamResolver returns the upstream device serving a unit ID and the
+// unit ID to address on it, or nil when the unit ID is not forwarded or
+// the policy denies the function code. Like MemoryResolver, policy
+// decisions live outside Modbus.
+type UpstreamResolver func(unitID uint8, functionCode uint8) (*Upstream, uint8)
+
+// Gateway exception codes.
+const (
+	excGatewayPathUnavailable = 0x0A
+	excGatewayTargetFailed    = 0x0B
+)
+
+// upstreamFor resolves the gateway route of a request, if any.
+func (l *listener) upstreamFor(unitID uint8, fc uint8) (*Upstream, uint8) {
+	if l.upstream == nil {
+		return nil, 0
+	}
+	return l.upstream(unitID, fc)
+}
+
+// forward proxies a request to an upstream device and returns the
+// device's response, or a gateway exception when it cannot be reached.
+func forward(up *Upstream, unitID uint8, pdu PDU) []byte {
+	resp, err := up.Do(unitID, pdu)
+	switch {
+	case errors.Is(err, errUpstreamBusy):
+		return exception(pdu.Function, excGatewayPathUnavailable)
+	case err != nil:
+		return exception(pdu.Function, excGatewayTargetFailed)
+	}
+	return resp
+}
@@ -0,0 +1,245 @@
+package modbus
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startUpstreamDevice serves a conformance memory on unit 1 over
+// loopback TCP and returns its address.
+func startUpstreamDevice(t *testing.T) string {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	l := newListener(unitOneResolver(mem), Policy{})
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handleConn(conn, FramingMBAP, l)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+// newGateway forwards unit 10 to unit 1 on up.
+func newGateway(up *Upstream) *listener {
+	return newListener(nil, Policy{
+		Upstream: func(unitID uint8, fc uint8) (*Upstream, uint8) {
+			if unitID != 10 {
+				return nil, 0
+			}
+			return up, 1
+		},
+	})
+}
+
+func TestGateway_Forward(t *testing.T) {
+	up := NewUpstream(startUpstreamDevice(t), 2, time.Second)
+	l := newGateway(up)
+
+	// Repeated requests reuse the pooled connection
+	for i := 0; i < 3; i++ {
+		resp := serveRequest(10, PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x02}}, l)
+		if want := []byte{0x03, 0x04, 0x00, 0x0A, 0x00, 0x0B}; !bytes.Equal(resp, want) {
+			t.Fatalf("expected % X, got % X", want, resp)
+		}
+	}
+
+	// Device exceptions pass through unchanged
+	resp := serveRequest(10, PDU{Function: 0x03, Data: []byte{0x00, 0x20, 0x00, 0x01}}, l)
+	if want := []byte{0x83, 0x02}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+func TestGateway_TargetDown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	l := newGateway(NewUpstream(addr, 1, 200*time.Millisecond))
+
+	resp := serveRequest(10, PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}}, l)
+	if want := []byte{0x83, 0x0B}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+func TestGateway_SilentTarget(t *testing.T) {
+	// A device that accepts but never answers
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	l := newGateway(NewUpstream(ln.Addr().String(), 1, 100*time.Millisecond))
+
+	resp := serveRequest(10, PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}}, l)
+	if want := []byte{0x83, 0x0B}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+func TestGateway_PoolExhausted(t *testing.T) {
+	up := NewUpstream(startUpstreamDevice(t), 1, 100*time.Millisecond)
+	l := newGateway(up)
+
+	// The only connection stays busy
+	up.slots <- struct{}{}
+
+	resp := serveRequest(10, PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}}, l)
+	if want := []byte{0x83, 0x0A}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+// startScriptedDevice answers FC 06 by echo. Each connection closes
+// after closeAfter answered requests; with drop, it then reads one more
+// request and closes without answering. received counts requests read.
+func startScriptedDevice(t *testing.T, closeAfter int, drop bool, received *atomic.Int32) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 12) // FC 06 request
+				for i := 0; ; i++ {
+					if i == closeAfter && !drop {
+						return
+					}
+					if _, err := io.ReadFull(conn, buf); err != nil {
+						return
+					}
+					received.Add(1)
+					if i == closeAfter {
+						return // executed, never answered
+					}
+					if _, err := conn.Write(buf); err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+var writeHR2 = PDU{Function: 0x06, Data: []byte{0x00, 0x02, 0x12, 0x34}}
+
+func TestGateway_NoRetryAfterWrite(t *testing.T) {
+	var received atomic.Int32
+	l := newGateway(NewUpstream(startScriptedDevice(t, 1, true, &received), 1, time.Second))
+
+	if resp := serveRequest(10, writeHR2, l); resp[0] != 0x06 {
+		t.Fatalf("expected echo, got % X", resp)
+	}
+
+	// The device read the write on the pooled connection, then dropped it
+	resp := serveRequest(10, writeHR2, l)
+	if want := []byte{0x86, 0x0B}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+	if n := received.Load(); n != 2 {
+		t.Fatalf("device received %d writes, expected 2 (no resend)", n)
+	}
+}
+
+func TestGateway_StaleIdleConnection(t *testing.T) {
+	var received atomic.Int32
+	l := newGateway(NewUpstream(startScriptedDevice(t, 1, false, &received), 1, time.Second))
+
+	if resp := serveRequest(10, writeHR2, l); resp[0] != 0x06 {
+		t.Fatalf("expected echo, got % X", resp)
+	}
+	time.Sleep(50 * time.Millisecond) // device closes the idle connection
+
+	// A new connection replaces the dropped one
+	if resp := serveRequest(10, writeHR2, l); resp[0] != 0x06 {
+		t.Fatalf("expected echo, got % X", resp)
+	}
+	if n := received.Load(); n != 2 {
+		t.Fatalf("device received %d writes, expected 2", n)
+	}
+}
+
+func TestGateway_CloseReleasesConnections(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	// Echo one FC 06 request, then wait for the gateway to hang up
+	closed := make(chan struct{})
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		buf := make([]byte, 12)
+		if _, err := io.ReadFull(conn, buf); err == nil {
+			conn.Write(buf)
+			io.ReadFull(conn, buf)
+		}
+		close(closed)
+	}()
+
+	up := NewUpstream(ln.Addr().String(), 1, time.Second)
+	l := newGateway(up)
+	if resp := serveRequest(10, writeHR2, l); resp[0] != 0x06 {
+		t.Fatalf("expected echo, got % X", resp)
+	}
+
+	up.Close()
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("pooled connection still open after Close")
+	}
+
+	// Later requests fail without dialing again
+	resp := serveRequest(10, writeHR2, l)
+	if want := []byte{0x86, 0x0B}; !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
@@ -2,65 +2,197 @@ package modbus
 
 import (
 	"encoding/binary"
+	"errors"
+	"log"
 	"net"
-
-	"modbus-memory-appliance/internal/core"
 )
 
 // handleConn handles a single Modbus TCP connection.
-func handleConn(conn net.Conn, resolve MemoryResolver) {
+func handleConn(
+	conn net.Conn,
+	framing Framing,
+	l *listener,
+) {
+	serveSession(conn, newSession(conn), framing, l)
+}
+
+// serveSession serves a connection whose shutdown is driven by sess.
+func serveSession(
+	conn net.Conn,
+	sess *session,
+	framing Framing,
+	l *listener,
+) {
 	defer conn.Close()
 
+	serveFrames(
+		newFrameCodec(conn, framing),
+		conn.RemoteAddr().String(),
+		nil,
+		l,
+		sess,
+	)
+}
+
+// serveFrames runs the request/response loop over one frame codec.
+// When addressed is set, frames for other unit IDs are ignored silently.
+// Requests read after sess started closing are dropped unanswered.
+func serveFrames(
+	codec frameCodec,
+	peer string,
+	addressed func(unitID uint8) bool,
+	l *listener,
+	sess *session,
+) {
+	counters := l.counters
+
+	// Last line of defence: a bad frame must never take the listener down.
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("modbus: closing %s after panic: %v", peer, r)
+		}
+	}()
+
 	for {
-		mbap, err := readMBAP(conn)
-		if err != nil {
+		sess.awaitRequest()
+
+		unitID, pdu, err := codec.readRequest()
+		switch {
+		case errors.Is(err, errCorruptFrame):
+			// Dropped frame, stream resynchronised
+			counters.busCommErrors.Add(1)
+			continue
+		case errors.Is(err, errInvalidFrame):
+			counters.busCommErrors.Add(1)
+			return
+		case err != nil:
 			return
 		}
 
-		pdu, err := readPDU(conn, mbap.Length)
-		if err != nil {
+		if !sess.begin() {
 			return
 		}
+		ok := serveFrame(codec, unitID, pdu, addressed, l)
+		sess.end()
 
-		// Resolve memory (routing only)
-		mem := resolve(mbap.UnitID, pdu.Function)
+		if !ok {
+			return
+		}
+	}
+}
 
-		if mem == nil {
-			resp := exception(pdu.Function, 0x02) // Illegal Data Address
-			mbap.Length = uint16(len(resp) + 1)
-			writeMBAP(conn, mbap)
-			conn.Write(resp)
-			continue
+// serveFrame answers one request frame. It returns false when the
+// response could not be written.
+func serveFrame(
+	codec frameCodec,
+	unitID uint8,
+	pdu PDU,
+	addressed func(unitID uint8) bool,
+	l *listener,
+) bool {
+	// Multi-drop line: another device owns this address
+	if addressed != nil && !l.isBroadcast(unitID) && !addressed(unitID) {
+		l.counters.busMessages.Add(1)
+		return true
+	}
+
+	resp := serveRequest(unitID, pdu, l)
+	if resp == nil {
+		// Broadcast: nothing to send
+		return true
+	}
+
+	if err := codec.writeResponse(unitID, resp); err != nil {
+		l.counters.serverNoResponse.Add(1)
+		return false
+	}
+	return true
+}
+
+// serveRequest routes a single request and returns its response PDU,
+// or nil when no response must be sent (broadcast).
+// It is shared by every transport and keeps the listener counters.
+func serveRequest(unitID uint8, pdu PDU, l *listener) []byte {
+	l.counters.busMessages.Add(1)
+
+	if l.isBroadcast(unitID) {
+		resp := serveBroadcast(pdu, l)
+		if resp != nil {
+			l.counters.recordResponse(pdu.Function, resp)
 		}
+		return resp
+	}
 
-		// 🔒 STATE SEALING HARD GATE
-		// If memory is Pre-Run, ALL Modbus access is denied
-		if mem.IsPreRun() {
-			resp := exception(pdu.Function, 0x01) // Illegal Function
-			mbap.Length = uint16(len(resp) + 1)
-			writeMBAP(conn, mbap)
-			conn.Write(resp)
-			continue
+	resp := routeRequest(unitID, pdu, l)
+
+	l.counters.recordResponse(pdu.Function, resp)
+	return resp
+}
+
+func routeRequest(unitID uint8, pdu PDU, l *listener) []byte {
+	counters := l.counters
+
+	// Function code and structure before routing, in spec exception
+	// order: an unsupported function is 0x01 even for an unmapped unit
+	if code := validateRequest(pdu); code != 0 {
+		counters.serverMessages.Add(1)
+		return exception(pdu.Function, code)
+	}
+
+	// Gateway: the unit ID belongs to an upstream device
+	if up, remote := l.upstreamFor(unitID, pdu.Function); up != nil {
+		counters.serverMessages.Add(1)
+
+		if !l.allowsAccess(pdu) {
+			return exception(pdu.Function, 0x02) // Illegal Data Address
 		}
+		return forward(up, remote, pdu)
+	}
 
-		resp := handlePDU(pdu, mem)
+	// Resolve memory (routing only)
+	mem := l.resolve(unitID, pdu.Function)
+	if mem == nil {
+		return exception(pdu.Function, 0x02) // Illegal Data Address
+	}
 
-		mbap.Length = uint16(len(resp) + 1)
-		writeMBAP(conn, mbap)
-		conn.Write(resp)
+	// 🔒 STATE SEALING HARD GATE
+	// If memory is Pre-Run, ALL Modbus access is denied
+	if mem.IsPreRun() {
+		return exception(pdu.Function, 0x01) // Illegal Function
 	}
+
+	counters.serverMessages.Add(1)
+
+	// 🔒 Address ACL (port policy)
+	if !l.allowsAccess(pdu) {
+		return exception(pdu.Function, 0x02) // Illegal Data Address
+	}
+
+	// Link diagnostics (listener-scoped, no memory access)
+	switch pdu.Function {
+	case 0x08: // Diagnostics
+		return handleDiagnostics(pdu, counters)
+	case 0x0B: // Get Comm Event Counter
+		return handleGetCommEventCounter(pdu, counters)
+	case 0x11: // Report Server ID
+		return handleReportServerID(pdu, unitID)
+	}
+
+	return handlePDU(pdu, l.store(mem))
 }
 
-// handlePDU executes a Modbus PDU against a RUN-state memory.
-func handlePDU(pdu PDU, mem *core.Memory) []byte {
+// handlePDU executes a Modbus PDU against a RUN-state memory, seen
+// through the listener's data store (direct or view).
+// Caller guarantees: the PDU passed validateRequest.
+func handlePDU(pdu PDU, data dataStore) []byte {
 	switch pdu.Function {
 
 	case 0x03: // Read Holding Registers
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		count := binary.BigEndian.Uint16(pdu.Data[2:4])
 
-		values, err := mem.ReadHoldingRegs(
-			extToInternal(addr),
+		values, err := data.ReadHoldingRegs(
+			int(addr),
 			int(count),
 		)
 		if err != nil {
@@ -80,8 +212,8 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		val := binary.BigEndian.Uint16(pdu.Data[2:4])
 
-		if err := mem.WriteHoldingRegs(
-			extToInternal(addr),
+		if err := data.WriteHoldingRegs(
+			int(addr),
 			[]uint16{val},
 		); err != nil {
 			return exception(pdu.Function, 0x02)
@@ -93,8 +225,8 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		count := binary.BigEndian.Uint16(pdu.Data[2:4])
 
-		values, err := mem.ReadInputRegs(
-			extToInternal(addr),
+		values, err := data.ReadInputRegs(
+			int(addr),
 			int(count),
 		)
 		if err != nil {
@@ -114,8 +246,8 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		count := binary.BigEndian.Uint16(pdu.Data[2:4])
 
-		values, err := mem.ReadCoils(
-			extToInternal(addr),
+		values, err := data.ReadCoils(
+			int(addr),
 			int(count),
 		)
 		if err != nil {
@@ -134,8 +266,8 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		count := binary.BigEndian.Uint16(pdu.Data[2:4])
 
-		values, err := mem.ReadDiscreteInputs(
-			extToInternal(addr),
+		values, err := data.ReadDiscreteInputs(
+			int(addr),
 			int(count),
 		)
 		if err != nil {
@@ -164,8 +296,8 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 			return exception(pdu.Function, 0x03)
 		}
 
-		if err := mem.WriteCoils(
-			extToInternal(addr),
+		if err := data.WriteCoils(
+			int(addr),
 			[]bool{b},
 		); err != nil {
 			return exception(pdu.Function, 0x02)
@@ -176,16 +308,11 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 	case 0x0F: // Write Multiple Coils
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		count := binary.BigEndian.Uint16(pdu.Data[2:4])
-		byteCount := int(pdu.Data[4])
-
-		if byteCount != (int(count)+7)/8 {
-			return exception(pdu.Function, 0x03)
-		}
 
 		values := unpackBools(pdu.Data[5:], int(count))
 
-		if err := mem.WriteCoils(
-			extToInternal(addr),
+		if err := data.WriteCoils(
+			int(addr),
 			values,
 		); err != nil {
 			return exception(pdu.Function, 0x02)
@@ -200,19 +327,14 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 	case 0x10: // Write Multiple Holding Registers
 		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
 		count := binary.BigEndian.Uint16(pdu.Data[2:4])
-		byteCount := int(pdu.Data[4])
-
-		if byteCount != int(count)*2 {
-			return exception(pdu.Function, 0x03)
-		}
 
 		values := make([]uint16, count)
 		for i := 0; i < int(count); i++ {
 			values[i] = binary.BigEndian.Uint16(pdu.Data[5+i*2:])
 		}
 
-		if err := mem.WriteHoldingRegs(
-			extToInternal(addr),
+		if err := data.WriteHoldingRegs(
+			int(addr),
 			values,
 		); err != nil {
 			return exception(pdu.Function, 0x02)
@@ -224,6 +346,55 @@ func handlePDU(pdu PDU, mem *core.Memory) []byte {
 		binary.BigEndian.PutUint16(out[3:5], count)
 		return out
 
+	case 0x16: // Mask Write Register
+		addr := binary.BigEndian.Uint16(pdu.Data[0:2])
+		andMask := binary.BigEndian.Uint16(pdu.Data[2:4])
+		orMask := binary.BigEndian.Uint16(pdu.Data[4:6])
+
+		if err := data.MaskWriteHoldingReg(
+			int(addr),
+			andMask,
+			orMask,
+		); err != nil {
+			return exception(pdu.Function, 0x02)
+		}
+
+		return append([]byte{pdu.Function}, pdu.Data...)
+
+	case 0x17: // Read/Write Multiple Registers
+		readAddr := binary.BigEndian.Uint16(pdu.Data[0:2])
+		readCount := binary.BigEndian.Uint16(pdu.Data[2:4])
+		writeAddr := binary.BigEndian.Uint16(pdu.Data[4:6])
+		writeCount := binary.BigEndian.Uint16(pdu.Data[6:8])
+
+		values := make([]uint16, writeCount)
+		for i := 0; i < int(writeCount); i++ {
+			values[i] = binary.BigEndian.Uint16(pdu.Data[9+i*2:])
+		}
+
+		// Write happens before read, under one memory lock.
+		read, err := data.WriteReadHoldingRegs(
+			int(writeAddr),
+			values,
+			int(readAddr),
+			int(readCount),
+		)
+		if err != nil {
+			return exception(pdu.Function, 0x02)
+		}
+
+		out := make([]byte, 2+len(read)*2)
+		out[0] = pdu.Function
+		out[1] = uint8(len(read) * 2)
+
+		for i, v := range read {
+			binary.BigEndian.PutUint16(out[2+i*2:], v)
+		}
+		return out
+
+	case 0x2B: // Encapsulated Interface Transport
+		return handleEncapsulated(pdu, data)
+
 	default:
 		return exception(pdu.Function, 0x01)
 	}
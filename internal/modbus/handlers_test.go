@@ -0,0 +1,77 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestReadWriteMultipleRegisters(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	_ = mem.WriteHoldingRegs(0, []uint16{1, 2, 3, 4})
+
+	// read 0..3, write 2 registers at 1
+	pdu := PDU{
+		Function: 0x17,
+		Data: []byte{
+			0x00, 0x00, 0x00, 0x04,
+			0x00, 0x01, 0x00, 0x02,
+			0x04,
+			0x00, 0x0A, 0x00, 0x0B,
+		},
+	}
+
+	resp := handlePDU(pdu, memoryStore{mem: mem})
+	want := []byte{0x17, 0x08, 0x00, 0x01, 0x00, 0x0A, 0x00, 0x0B, 0x00, 0x04}
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected % X, got % X", want, resp)
+	}
+}
+
+func TestReadWriteMultipleRegisters_OutOfRangeWritesNothing(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+
+	// valid write, read past the end
+	pdu := PDU{
+		Function: 0x17,
+		Data: []byte{
+			0x00, 0x06, 0x00, 0x04,
+			0x00, 0x00, 0x00, 0x01,
+			0x02,
+			0x00, 0x63,
+		},
+	}
+
+	resp := handlePDU(pdu, memoryStore{mem: mem})
+	if !bytes.Equal(resp, []byte{0x97, 0x02}) {
+		t.Fatalf("expected exception 02, got % X", resp)
+	}
+
+	vals, _ := mem.ReadHoldingRegs(0, 1)
+	if vals[0] != 0 {
+		t.Fatalf("expected no write on rejected request, got %d", vals[0])
+	}
+}
+
+func TestMaskWriteRegister(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	_ = mem.WriteHoldingRegs(4, []uint16{0x0012})
+
+	// Example from the Modbus spec: AND=0x00F2, OR=0x0025 -> 0x0017
+	pdu := PDU{
+		Function: 0x16,
+		Data:     []byte{0x00, 0x04, 0x00, 0xF2, 0x00, 0x25},
+	}
+
+	resp := handlePDU(pdu, memoryStore{mem: mem})
+	want := append([]byte{0x16}, pdu.Data...)
+	if !bytes.Equal(resp, want) {
+		t.Fatalf("expected echo % X, got % X", want, resp)
+	}
+
+	vals, _ := mem.ReadHoldingRegs(4, 1)
+	if vals[0] != 0x0017 {
+		t.Fatalf("expected 0x0017, got 0x%04X", vals[0])
+	}
+}
@@ -0,0 +1,13 @@
+package modbus
+
+import "modbus-memory-appliance/internal/core"
+
+// unitOneResolver maps unit 1 to mem; every other unit is unmapped.
+func unitOneResolver(mem *core.Memory) MemoryResolver {
+	return func(unitID uint8, fc uint8) *core.Memory {
+		if unitID != 1 {
+			return nil
+		}
+		return mem
+	}
+}
@@ -0,0 +1,57 @@
+package modbus
+
+import "modbus-memory-appliance/internal/core"
+
+// Policy is the part of a port policy enforced inside the request path.
+// The zero value enforces nothing beyond routing.
+type Policy struct {
+	Access AccessCheck // optional address ACL
+	View   *View       // optional address remapping
+
+	// Broadcast applies writes on unit ID 0 to every reachable memory
+	// without a response.
+	Broadcast bool
+
+	// Upstream forwards unit IDs to remote devices (gateway mode).
+	Upstream UpstreamResolver
+}
+
+// listener is the state requests are served with: routing, the port
+// policy and the link counters. All connections of one configured
+// port (or serial line) share it.
+type listener struct {
+	resolve   MemoryResolver
+	upstream  UpstreamResolver
+	access    AccessCheck
+	view      *View
+	broadcast bool
+	counters  *commCounters
+}
+
+func newListener(resolve MemoryResolver, pol Policy) *listener {
+	return &listener{
+		resolve:   resolve,
+		upstream:  pol.Upstream,
+		access:    pol.Access,
+		view:      pol.View,
+		broadcast: pol.Broadcast,
+		counters:  newCommCounters(),
+	}
+}
+
+// store presents a resolved memory through the listener's view, if any.
+func (l *listener) store(mem *core.Memory) dataStore {
+	if l.view != nil {
+		return l.view.bind(mem)
+	}
+	return memoryStore{mem: mem}
+}
+
+// withResolver returns a copy that routes through resolve and upstream.
+// Counters stay shared with the listener.
+func (l *listener) withResolver(resolve MemoryResolver, upstream UpstreamResolver) *listener {
+	c := *l
+	c.resolve = resolve
+	c.upstream = upstream
+	return &c
+}
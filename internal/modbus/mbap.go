@@ -5,6 +5,9 @@ import (
 	"io"
 )
 
+// mbapHeaderLen is the MBAP header: transaction, protocol, length, unit ID.
+const mbapHeaderLen = 7
+
 type MBAP struct {
 	TransactionID uint16
 	ProtocolID    uint16
@@ -13,26 +16,94 @@ type MBAP struct {
 }
 
 func readMBAP(r io.Reader) (MBAP, error) {
-	var hdr [7]byte
+	var hdr [mbapHeaderLen]byte
 	_, err := io.ReadFull(r, hdr[:])
 	if err != nil {
 		return MBAP{}, err
 	}
 
+	return parseMBAP(hdr[:]), nil
+}
+
+func parseMBAP(hdr []byte) MBAP {
 	return MBAP{
 		TransactionID: binary.BigEndian.Uint16(hdr[0:2]),
 		ProtocolID:    binary.BigEndian.Uint16(hdr[2:4]),
 		Length:        binary.BigEndian.Uint16(hdr[4:6]),
 		UnitID:        hdr[6],
+	}
+}
+
+// readADU reads one MBAP request into buf (at least maxMBAPADU bytes).
+// The returned PDU aliases buf and is valid until buf is reused.
+func readADU(r io.Reader, buf []byte) (MBAP, PDU, error) {
+	if _, err := io.ReadFull(r, buf[:mbapHeaderLen]); err != nil {
+		return MBAP{}, PDU{}, err
+	}
+	mbap := parseMBAP(buf)
+
+	// Not Modbus: drop the connection
+	if mbap.ProtocolID != 0 {
+		return MBAP{}, PDU{}, errInvalidFrame
+	}
+
+	if mbap.Length < minMBAPLength || mbap.Length > maxMBAPLength {
+		return MBAP{}, PDU{}, errInvalidFrame
+	}
+
+	body := buf[mbapHeaderLen : mbapHeaderLen+int(mbap.Length)-1]
+	if _, err := io.ReadFull(r, body); err != nil {
+		return MBAP{}, PDU{}, err
+	}
+
+	return mbap, PDU{
+		Function: body[0],
+		Data:     body[1:],
 	}, nil
 }
 
-func writeMBAP(w io.Writer, mbap MBAP) error {
-	var hdr [7]byte
-	binary.BigEndian.PutUint16(hdr[0:2], mbap.TransactionID)
-	binary.BigEndian.PutUint16(hdr[2:4], mbap.ProtocolID)
-	binary.BigEndian.PutUint16(hdr[4:6], mbap.Length)
-	hdr[6] = mbap.UnitID
-	_, err := w.Write(hdr[:])
+// writeADU sends a response PDU in one write, echoing the transaction
+// header of the request.
+func writeADU(w io.Writer, req MBAP, unitID uint8, pdu []byte) error {
+	frame := getFrame()
+	defer putFrame(frame)
+
+	out := (*frame)[:mbapHeaderLen+len(pdu)]
+	binary.BigEndian.PutUint16(out[0:2], req.TransactionID)
+	binary.BigEndian.PutUint16(out[2:4], req.ProtocolID)
+	binary.BigEndian.PutUint16(out[4:6], uint16(len(pdu)+1))
+	out[6] = unitID
+	copy(out[mbapHeaderLen:], pdu)
+
+	_, err := w.Write(out)
 	return err
 }
+
+// mbapCodec carries PDUs in MBAP frames (Modbus TCP).
+// Requests are read into one buffer owned by the codec.
+type mbapCodec struct {
+	rw   io.ReadWriter
+	last MBAP
+	buf  [maxMBAPADU]byte
+}
+
+func newMBAPCodec(rw io.ReadWriter) *mbapCodec {
+	return &mbapCodec{rw: rw}
+}
+
+// readRequest returns the next request. Its PDU is valid until the
+// next call.
+func (c *mbapCodec) readRequest() (uint8, PDU, error) {
+	mbap, pdu, err := readADU(c.rw, c.buf[:])
+	if err != nil {
+		return 0, PDU{}, err
+	}
+
+	c.last = mbap
+	return mbap.UnitID, pdu, nil
+}
+
+// writeResponse echoes the transaction header of the last request.
+func (c *mbapCodec) writeResponse(unitID uint8, resp []byte) error {
+	return writeADU(c.rw, c.last, unitID, resp)
+}
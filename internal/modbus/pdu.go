@@ -2,12 +2,22 @@ package modbus
 
 import "io"
 
+// MBAP length covers the unit ID plus the PDU (1..253 bytes).
+const (
+	minMBAPLength = 2
+	maxMBAPLength = 254
+)
+
 type PDU struct {
 	Function uint8
 	Data     []byte
 }
 
 func readPDU(r io.Reader, length uint16) (PDU, error) {
+	if length < minMBAPLength || length > maxMBAPLength {
+		return PDU{}, errInvalidFrame
+	}
+
 	buf := make([]byte, length-1)
 	_, err := io.ReadFull(r, buf)
 	if err != nil {
@@ -0,0 +1,119 @@
+package modbus
+
+import (
+	"errors"
+	"log"
+	"net"
+)
+
+// pipelined is one request of a pipelined connection. done is closed
+// once resp is set.
+type pipelined struct {
+	mbap MBAP
+	resp []byte
+	done chan struct{}
+}
+
+// servePipelined serves an MBAP connection, handling up to depth
+// requests concurrently. Responses are written in request order, each
+// in a single write. Like serveSession it closes conn when done.
+func servePipelined(
+	conn net.Conn,
+	sess *session,
+	depth int,
+	l *listener,
+) {
+	defer conn.Close()
+
+	// The writer holds one request, the queue the rest
+	queue := make(chan *pipelined, depth-1)
+	written := make(chan struct{})
+
+	go func() {
+		defer close(written)
+		writePipelined(conn, queue, l, sess)
+	}()
+
+	readPipelined(conn, queue, l, sess)
+	close(queue)
+	<-written
+}
+
+// readPipelined reads requests and starts each one as soon as the
+// pipeline has room.
+func readPipelined(
+	conn net.Conn,
+	queue chan<- *pipelined,
+	l *listener,
+	sess *session,
+) {
+	peer := conn.RemoteAddr().String()
+
+	// Last line of defence, as in serveFrames
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("modbus: closing %s after panic: %v", peer, r)
+		}
+	}()
+
+	for {
+		sess.awaitRequest()
+
+		frame := getFrame()
+		mbap, pdu, err := readADU(conn, *frame)
+		if err != nil {
+			putFrame(frame)
+			if errors.Is(err, errInvalidFrame) {
+				l.counters.busCommErrors.Add(1)
+			}
+			return
+		}
+
+		if !sess.begin() {
+			putFrame(frame)
+			return
+		}
+
+		req := &pipelined{mbap: mbap, done: make(chan struct{})}
+		queue <- req // blocks while the pipeline is full
+
+		go func() {
+			defer close(req.done)
+			defer putFrame(frame)
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("modbus: closing %s after panic: %v", peer, r)
+					conn.Close()
+				}
+			}()
+
+			req.resp = serveRequest(mbap.UnitID, pdu, l)
+		}()
+	}
+}
+
+// writePipelined writes responses in request order. After a failed
+// write the connection is closed and remaining requests are dropped.
+func writePipelined(
+	conn net.Conn,
+	queue <-chan *pipelined,
+	l *listener,
+	sess *session,
+) {
+	failed := false
+
+	for req := range queue {
+		<-req.done
+
+		// nil: broadcast, nothing to send
+		if req.resp != nil && !failed {
+			if err := writeADU(conn, req.mbap, req.mbap.UnitID, req.resp); err != nil {
+				l.counters.serverNoResponse.Add(1)
+				failed = true
+				conn.Close() // stops the reader
+			}
+		}
+
+		sess.end()
+	}
+}
@@ -0,0 +1,151 @@
+package modbus
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// startPipelinedServer serves units 1 and 2 with the given pipeline
+// depth. Requests for unit 1 take slow, unit 2 answers at once.
+func startPipelinedServer(t *testing.T, depth int, slow time.Duration) *Server {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	resolve := func(unitID uint8, fc uint8) *core.Memory {
+		if unitID == 1 {
+			time.Sleep(slow)
+		}
+		return mem
+	}
+
+	srv, err := Listen(Config{Addr: "127.0.0.1:0", PipelineDepth: depth}, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Shutdown(context.Background()) })
+	return srv
+}
+
+// readRequestTID builds an FC 03 request with its own transaction ID.
+func readRequestTID(tid uint16, unitID uint8) []byte {
+	req := adu(unitID, 0x03, 0x00, 0x00, 0x00, 0x01)
+	binary.BigEndian.PutUint16(req[0:2], tid)
+	return req
+}
+
+// readTIDs reads n FC 03 responses and returns their transaction IDs.
+func readTIDs(t *testing.T, c net.Conn, n int) []uint16 {
+	t.Helper()
+
+	var tids []uint16
+	resp := make([]byte, 11)
+	for range n {
+		if _, err := io.ReadFull(c, resp); err != nil {
+			t.Fatalf("after %d responses: %v", len(tids), err)
+		}
+		tids = append(tids, binary.BigEndian.Uint16(resp[0:2]))
+	}
+	return tids
+}
+
+func TestPipeline_ResponsesInRequestOrder(t *testing.T) {
+	srv := startPipelinedServer(t, 4, 100*time.Millisecond)
+	c := dialServer(t, srv)
+
+	// The slow request finishes last but is answered first
+	var reqs []byte
+	reqs = append(reqs, readRequestTID(1, 1)...)
+	reqs = append(reqs, readRequestTID(2, 2)...)
+	reqs = append(reqs, readRequestTID(3, 2)...)
+	if _, err := c.Write(reqs); err != nil {
+		t.Fatal(err)
+	}
+
+	tids := readTIDs(t, c, 3)
+	for i, tid := range tids {
+		if tid != uint16(i+1) {
+			t.Fatalf("expected responses in request order, got %v", tids)
+		}
+	}
+}
+
+func TestPipeline_Concurrent(t *testing.T) {
+	const slow = 100 * time.Millisecond
+
+	srv := startPipelinedServer(t, 4, slow)
+	c := dialServer(t, srv)
+
+	var reqs []byte
+	for tid := range uint16(4) {
+		reqs = append(reqs, readRequestTID(tid, 1)...)
+	}
+
+	start := time.Now()
+	if _, err := c.Write(reqs); err != nil {
+		t.Fatal(err)
+	}
+	readTIDs(t, c, 4)
+
+	// One at a time would take 4 × slow
+	if elapsed := time.Since(start); elapsed >= 3*slow {
+		t.Fatalf("expected requests handled concurrently, took %v", elapsed)
+	}
+}
+
+func TestPipeline_ShutdownDrainsInFlight(t *testing.T) {
+	srv := startPipelinedServer(t, 4, 200*time.Millisecond)
+	c := dialServer(t, srv)
+
+	reqs := append(readRequestTID(1, 1), readRequestTID(2, 1)...)
+	if _, err := c.Write(reqs); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Shutdown(context.Background()) }()
+
+	// Both requests in flight are answered before the connection closes
+	readTIDs(t, c, 2)
+	if _, err := c.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected connection closed after drain, got %v", err)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestPipeline_RequiresMBAP(t *testing.T) {
+	_, err := Listen(Config{Addr: "127.0.0.1:0", Framing: FramingRTU, PipelineDepth: 4}, nil)
+	if err == nil {
+		t.Fatal("expected pipelining rejected for rtu framing")
+	}
+}
+
+// writeCounter counts Write calls.
+type writeCounter struct{ writes int }
+
+func (w *writeCounter) Write(p []byte) (int, error) {
+	w.writes++
+	return len(p), nil
+}
+
+func TestWriteADU_SingleWrite(t *testing.T) {
+	var w writeCounter
+	resp := []byte{0x03, 0x02, 0x00, 0x0A}
+
+	if err := writeADU(&w, MBAP{TransactionID: 7}, 1, resp); err != nil {
+		t.Fatal(err)
+	}
+	if w.writes != 1 {
+		t.Fatalf("expected one write per response, got %d", w.writes)
+	}
+}
@@ -0,0 +1,203 @@
+package modbus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// rtuTCPFrameGap is the inter-frame silence used for RTU over TCP.
+// TCP may split a frame across segments, so this is far longer than
+// the serial t3.5 character time.
+const rtuTCPFrameGap = 100 * time.Millisecond
+
+// rtuMaxFrame is the largest RTU frame: address + 253 byte PDU + CRC.
+const rtuMaxFrame = 256
+
+// readDeadliner is implemented by net.Conn and *os.File.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// rtuCodec carries PDUs in RTU frames: address, PDU, CRC16 (low byte first).
+//
+// Frames have no length field. The request length is derived from the
+// function code; unknown function codes end at the first inter-frame gap.
+// Once a frame has started, every byte must follow within the gap, otherwise
+// the partial frame is dropped.
+type rtuCodec struct {
+	br  *bufio.Reader
+	w   io.Writer
+	d   readDeadliner
+	gap time.Duration
+}
+
+func newRTUCodec(rw io.ReadWriter, d readDeadliner, gap time.Duration) *rtuCodec {
+	return &rtuCodec{
+		br:  bufio.NewReaderSize(rw, rtuMaxFrame),
+		w:   rw,
+		d:   d,
+		gap: gap,
+	}
+}
+
+func (c *rtuCodec) readRequest() (uint8, PDU, error) {
+	// Wait for the start of the next frame without a deadline
+	if err := c.d.SetReadDeadline(time.Time{}); err != nil {
+		return 0, PDU{}, err
+	}
+
+	first, err := c.br.ReadByte()
+	if err != nil {
+		return 0, PDU{}, err
+	}
+
+	frame := make([]byte, 1, rtuMaxFrame)
+	frame[0] = first
+
+	for {
+		n := rtuRequestLength(frame)
+
+		if n == 0 {
+			// Unknown function code: the frame ends at silence
+			frame, err = c.readUntilSilence(frame)
+			if err != nil {
+				return 0, PDU{}, err
+			}
+			break
+		}
+
+		if n > rtuMaxFrame {
+			return 0, PDU{}, c.resync()
+		}
+
+		if n <= len(frame) {
+			break
+		}
+
+		chunk := make([]byte, n-len(frame))
+		if err := c.readMidFrame(chunk); err != nil {
+			return 0, PDU{}, err
+		}
+		frame = append(frame, chunk...)
+	}
+
+	if len(frame) < 4 || !rtuCRCValid(frame) {
+		return 0, PDU{}, c.resync()
+	}
+
+	return frame[0], PDU{
+		Function: frame[1],
+		Data:     frame[2 : len(frame)-2],
+	}, nil
+}
+
+func (c *rtuCodec) writeResponse(unitID uint8, resp []byte) error {
+	frame := make([]byte, 0, len(resp)+3)
+	frame = append(frame, unitID)
+	frame = append(frame, resp...)
+	frame = binary.LittleEndian.AppendUint16(frame, crc16(frame))
+
+	_, err := c.w.Write(frame)
+	return err
+}
+
+// readMidFrame reads the remainder of a started frame. Silence longer than
+// the gap drops the partial frame.
+func (c *rtuCodec) readMidFrame(buf []byte) error {
+	if err := c.d.SetReadDeadline(time.Now().Add(c.gap)); err != nil {
+		return err
+	}
+
+	_, err := io.ReadFull(c.br, buf)
+	if isTimeout(err) {
+		return errCorruptFrame
+	}
+	return err
+}
+
+// readUntilSilence appends bytes until the inter-frame gap is observed.
+func (c *rtuCodec) readUntilSilence(frame []byte) ([]byte, error) {
+	for {
+		if err := c.d.SetReadDeadline(time.Now().Add(c.gap)); err != nil {
+			return nil, err
+		}
+
+		b, err := c.br.ReadByte()
+		if isTimeout(err) {
+			return frame, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(frame) == rtuMaxFrame {
+			return nil, c.resync()
+		}
+		frame = append(frame, b)
+	}
+}
+
+// resync discards input until the line is silent for one gap,
+// so the next read starts on a frame boundary.
+func (c *rtuCodec) resync() error {
+	_, _ = c.br.Discard(c.br.Buffered())
+
+	for {
+		if err := c.d.SetReadDeadline(time.Now().Add(c.gap)); err != nil {
+			return err
+		}
+
+		_, err := c.br.ReadByte()
+		if isTimeout(err) {
+			return errCorruptFrame
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// rtuRequestLength returns the total request frame length (CRC included)
+// known from the bytes received so far. It may grow as more header bytes
+// arrive. 0 means the function code does not define a request length.
+func rtuRequestLength(frame []byte) int {
+	if len(frame) < 2 {
+		return 2
+	}
+
+	switch frame[1] {
+	case 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x08:
+		return 8
+	case 0x0B, 0x11:
+		return 4
+	case 0x16:
+		return 10
+	case 0x2B:
+		return 7
+	case 0x0F, 0x10:
+		if len(frame) < 7 {
+			return 7
+		}
+		return 7 + int(frame[6]) + 2
+	case 0x17:
+		if len(frame) < 11 {
+			return 11
+		}
+		return 11 + int(frame[10]) + 2
+	default:
+		return 0
+	}
+}
+
+func rtuCRCValid(frame []byte) bool {
+	n := len(frame)
+	return crc16(frame[:n-2]) == binary.LittleEndian.Uint16(frame[n-2:])
+}
+
+func isTimeout(err error) bool {
+	return errors.Is(err, os.ErrDeadlineExceeded)
+}
@@ -0,0 +1,118 @@
+package modbus
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// Parity of a serial line.
+type Parity string
+
+const (
+	ParityNone Parity = "none"
+	ParityEven Parity = "even"
+	ParityOdd  Parity = "odd"
+)
+
+// SerialConfig describes one Modbus serial line served as a slave.
+type SerialConfig struct {
+	Device   string
+	BaudRate int
+	DataBits int    // default 8
+	Parity   Parity // default even
+	StopBits int    // default 1
+
+	// SilentInterval is the t3.5 inter-frame gap.
+	// 0 derives it from the baud rate.
+	SilentInterval time.Duration
+
+	Framing Framing // rtu (default) or ascii
+
+	// Addressed reports whether this slave answers for a unit ID.
+	// Frames for other addresses belong to other devices on the
+	// multi-drop line and are ignored. nil answers every address.
+	Addressed func(unitID uint8) bool
+
+	// Policy is enforced in the request path of the line.
+	Policy
+}
+
+// StartSerial serves Modbus requests on a serial device until ctx is
+// cancelled or the device fails. Requests are routed by slave address
+// through the resolver. On cancellation the request in flight is
+// answered before the line is closed, and nil is returned.
+func StartSerial(ctx context.Context, cfg SerialConfig, resolve MemoryResolver) error {
+	cfg = serialDefaults(cfg)
+
+	f, err := openSerial(cfg)
+	if err != nil {
+		return fmt.Errorf("serial %s: %w", cfg.Device, err)
+	}
+	defer f.Close()
+
+	log.Println(
+		"Modbus serial listening on", cfg.Device,
+		"baud =", cfg.BaudRate,
+		"framing =", cfg.Framing,
+		"t3.5 =", cfg.SilentInterval,
+	)
+
+	sess := newSession(f)
+	stop := context.AfterFunc(ctx, sess.closeIdle)
+	defer stop()
+
+	serveSerial(f, cfg, newListener(resolve, cfg.Policy), sess)
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return fmt.Errorf("serial %s: line closed", cfg.Device)
+}
+
+// serveSerial runs the request loop on an opened and configured line.
+func serveSerial(
+	f *os.File,
+	cfg SerialConfig,
+	l *listener,
+	sess *session,
+) {
+	var codec frameCodec
+	if cfg.Framing == FramingASCII {
+		codec = newASCIICodec(f)
+	} else {
+		codec = newRTUCodec(f, f, cfg.SilentInterval)
+	}
+
+	serveFrames(codec, cfg.Device, cfg.Addressed, l, sess)
+}
+
+func serialDefaults(cfg SerialConfig) SerialConfig {
+	if cfg.DataBits == 0 {
+		cfg.DataBits = 8
+	}
+	if cfg.Parity == "" {
+		cfg.Parity = ParityEven
+	}
+	if cfg.StopBits == 0 {
+		cfg.StopBits = 1
+	}
+	if cfg.Framing == "" {
+		cfg.Framing = FramingRTU
+	}
+	if cfg.SilentInterval <= 0 {
+		cfg.SilentInterval = silentInterval(cfg.BaudRate)
+	}
+	return cfg
+}
+
+// silentInterval returns t3.5 for a baud rate. Above 19200 baud the spec
+// fixes it at 1.75 ms. One character is 11 bits on the wire.
+func silentInterval(baud int) time.Duration {
+	if baud <= 0 || baud > 19200 {
+		return 1750 * time.Microsecond
+	}
+	return time.Duration(float64(time.Second) * 3.5 * 11 / float64(baud))
+}
@@ -0,0 +1,98 @@
+//go:build linux
+
+package modbus
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+var baudRates = map[int]uint32{
+	1200:   unix.B1200,
+	2400:   unix.B2400,
+	4800:   unix.B4800,
+	9600:   unix.B9600,
+	19200:  unix.B19200,
+	38400:  unix.B38400,
+	57600:  unix.B57600,
+	115200: unix.B115200,
+	230400: unix.B230400,
+}
+
+// openSerial opens a TTY in raw mode with the configured line settings.
+// The file is non-blocking so read deadlines (inter-frame gaps) work.
+func openSerial(cfg SerialConfig) (*os.File, error) {
+	speed, ok := baudRates[cfg.BaudRate]
+	if !ok {
+		return nil, fmt.Errorf("unsupported baud rate %d", cfg.BaudRate)
+	}
+
+	f, err := os.OpenFile(cfg.Device, os.O_RDWR|unix.O_NOCTTY|unix.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	rc, err := f.SyscallConn()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	var termErr error
+	err = rc.Control(func(fd uintptr) {
+		termErr = setTermios(int(fd), cfg, speed)
+	})
+	if err == nil {
+		err = termErr
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func setTermios(fd int, cfg SerialConfig, speed uint32) error {
+	t, err := unix.IoctlGetTermios(fd, unix.TCGETS)
+	if err != nil {
+		return err
+	}
+
+	// Raw mode: no echo, no line editing, no character translation
+	t.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP |
+		unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON | unix.IXOFF | unix.IXANY
+	t.Oflag &^= unix.OPOST
+	t.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+
+	t.Cflag &^= unix.CSIZE | unix.PARENB | unix.PARODD | unix.CSTOPB |
+		unix.CRTSCTS | unix.CBAUD
+	t.Cflag |= unix.CREAD | unix.CLOCAL | speed
+	t.Ispeed = speed
+	t.Ospeed = speed
+
+	if cfg.DataBits == 7 {
+		t.Cflag |= unix.CS7
+	} else {
+		t.Cflag |= unix.CS8
+	}
+
+	switch cfg.Parity {
+	case ParityEven:
+		t.Cflag |= unix.PARENB
+	case ParityOdd:
+		t.Cflag |= unix.PARENB | unix.PARODD
+	}
+
+	if cfg.StopBits == 2 {
+		t.Cflag |= unix.CSTOPB
+	}
+
+	// Byte-at-a-time reads; frame timing is done with read deadlines
+	t.Cc[unix.VMIN] = 1
+	t.Cc[unix.VTIME] = 0
+
+	return unix.IoctlSetTermios(fd, unix.TCSETS, t)
+}
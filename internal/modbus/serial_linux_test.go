@@ -0,0 +1,131 @@
+//go:build linux
+
+package modbus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the master side of a pseudo-terminal pair and the
+// path of its slave device, which stands in for an RS-485 adapter.
+func openPTY(t *testing.T) (*os.File, string) {
+	t.Helper()
+
+	master, err := os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		t.Skipf("no pseudo-terminal support: %v", err)
+	}
+	t.Cleanup(func() { master.Close() })
+
+	rc, err := master.SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int
+	var ioctlErr error
+	err = rc.Control(func(fd uintptr) {
+		if ioctlErr = unix.IoctlSetPointerInt(int(fd), unix.TIOCSPTLCK, 0); ioctlErr != nil {
+			return
+		}
+		n, ioctlErr = unix.IoctlGetInt(int(fd), unix.TIOCGPTN)
+	})
+	if err == nil {
+		err = ioctlErr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return master, fmt.Sprintf("/dev/pts/%d", n)
+}
+
+func startSerialOnPTY(t *testing.T) (*os.File, *commCounters) {
+	t.Helper()
+
+	master, slave := openPTY(t)
+
+	mem := newConformanceMemory()
+	cfg := serialDefaults(SerialConfig{
+		Device:    slave,
+		BaudRate:  19200,
+		Addressed: func(unitID uint8) bool { return unitID == 1 },
+	})
+
+	f, err := openSerial(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+
+	l := newListener(unitOneResolver(mem), Policy{})
+	go serveSerial(f, cfg, l, newSession(f))
+	counters := l.counters
+
+	_ = master.SetDeadline(time.Now().Add(2 * time.Second))
+	return master, counters
+}
+
+func TestSilentInterval(t *testing.T) {
+	// 9600 baud: 3.5 chars * 11 bits / 9600 = ~4.01 ms
+	if got := silentInterval(9600); got < 4*time.Millisecond || got > 4100*time.Microsecond {
+		t.Fatalf("unexpected t3.5 at 9600 baud: %v", got)
+	}
+	if got := silentInterval(115200); got != 1750*time.Microsecond {
+		t.Fatalf("expected fixed 1.75ms above 19200 baud, got %v", got)
+	}
+}
+
+func TestSerialRTU_ReadHoldingRegisters(t *testing.T) {
+	master, _ := startSerialOnPTY(t)
+
+	if _, err := master.Write(withCRC(0x01, 0x03, 0x00, 0x00, 0x00, 0x02)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := withCRC(0x01, 0x03, 0x04, 0x00, 0x0A, 0x00, 0x0B)
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(master, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestSerialRTU_IgnoresOtherSlaves(t *testing.T) {
+	master, counters := startSerialOnPTY(t)
+
+	// Addressed to slave 2 (another device on the line): no answer
+	if _, err := master.Write(withCRC(0x02, 0x03, 0x00, 0x00, 0x00, 0x01)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := master.Write(withCRC(0x01, 0x06, 0x00, 0x03, 0x00, 0x2A)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := withCRC(0x01, 0x06, 0x00, 0x03, 0x00, 0x2A)
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(master, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+
+	if n := counters.busMessages.Load(); n != 2 {
+		t.Fatalf("expected 2 bus messages, got %d", n)
+	}
+	if n := counters.serverMessages.Load(); n != 1 {
+		t.Fatalf("expected 1 server message, got %d", n)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !linux
+
+package modbus
+
+import (
+	"errors"
+	"os"
+)
+
+func openSerial(cfg SerialConfig) (*os.File, error) {
+	return nil, errors.New("serial transport is only supported on linux")
+}
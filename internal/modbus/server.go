@@ -1,58 +1,183 @@
 package modbus
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
 	"log"
 	"net"
+	"sync"
+	"time"
 
 	"modbus-memory-appliance/internal/modbus/ipfilter"
 )
 
 const defaultMaxConnections = 32
 
-func Start(
-	addr string,
-	resolve MemoryResolver,
-	allowIPs []string,
-	denyIPs []string,
-	maxConns int,
-) error {
+// Config describes one Modbus TCP listener.
+type Config struct {
+	Addr           string
+	AllowIPs       []string
+	DenyIPs        []string
+	MaxConnections int
+	Framing        Framing
+	Transport      Transport
 
-	filter, err := ipfilter.Compile(allowIPs, denyIPs)
+	// Connection timeouts (TCP only, 0 = none). IdleTimeout bounds the
+	// wait for the next request, RequestTimeout reading the rest of a
+	// started request and writing its response.
+	IdleTimeout    time.Duration
+	RequestTimeout time.Duration
+
+	// KeepAlive configures TCP keepalive probes on accepted
+	// connections. nil keeps the Go defaults; Enable=false disables.
+	KeepAlive *net.KeepAliveConfig
+
+	// PipelineDepth is how many requests of one connection are handled
+	// concurrently (MBAP framing only). Responses keep request order.
+	// 0 or 1 handles requests one at a time.
+	PipelineDepth int
+
+	// Policy is enforced in the request path of every connection.
+	Policy
+
+	// TLS enables Modbus/TCP Security. Each client is then served
+	// with the resolvers Roles returns for its certificate role;
+	// the resolver passed to Start and Policy.Upstream are not used.
+	TLS   *tls.Config
+	Roles RoleResolver
+}
+
+// ErrServerClosed is returned by Serve after Shutdown.
+var ErrServerClosed = errors.New("modbus: server closed")
+
+// Server is one Modbus TCP or UDP listener. It runs until Shutdown.
+type Server struct {
+	cfg      Config
+	framing  Framing
+	maxConns int
+	filter   *ipfilter.Filter
+	l        *listener
+	stats    connStats
+
+	ln net.Listener   // tcp
+	pc net.PacketConn // udp
+
+	mu       sync.Mutex
+	closed   bool
+	sessions map[*session]struct{}
+	wg       sync.WaitGroup
+}
+
+// Start listens and serves until the listener fails.
+// Use Listen for a server that can be shut down.
+func Start(cfg Config, resolve MemoryResolver) error {
+	srv, err := Listen(cfg, resolve)
 	if err != nil {
 		return err
 	}
+	return srv.Serve()
+}
 
-	ln, err := net.Listen("tcp", addr)
+// Listen binds the listener socket. Requests are served by Serve.
+func Listen(cfg Config, resolve MemoryResolver) (*Server, error) {
+	filter, err := ipfilter.Compile(cfg.AllowIPs, cfg.DenyIPs)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	s := &Server{
+		cfg:      cfg,
+		framing:  cfg.Framing,
+		maxConns: cfg.MaxConnections,
+		filter:   filter,
+		l:        newListener(resolve, cfg.Policy),
+		sessions: make(map[*session]struct{}),
+	}
+
+	if cfg.Transport == TransportUDP {
+		if cfg.TLS != nil {
+			return nil, errors.New("modbus: tls requires tcp transport")
+		}
+
+		s.pc, err = net.ListenPacket("udp", cfg.Addr)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
 	}
 
-	if maxConns <= 0 {
-		maxConns = defaultMaxConnections
+	if cfg.TLS != nil && cfg.Roles == nil {
+		return nil, errors.New("modbus: tls listener without role resolver")
 	}
 
-	log.Println("Modbus TCP listening on", addr, "max_connections =", maxConns)
+	if s.maxConns <= 0 {
+		s.maxConns = defaultMaxConnections
+	}
+	if s.framing == "" {
+		s.framing = FramingMBAP
+	}
+	if cfg.PipelineDepth > 1 && s.framing != FramingMBAP {
+		return nil, errors.New("modbus: pipelining requires mbap framing")
+	}
 
-	sem := make(chan struct{}, maxConns)
+	var lc net.ListenConfig
+	if cfg.KeepAlive != nil {
+		lc.KeepAliveConfig = *cfg.KeepAlive
+		if !cfg.KeepAlive.Enable {
+			lc.KeepAlive = -1
+		}
+	}
+
+	s.ln, err = lc.Listen(context.Background(), "tcp", cfg.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.TLS != nil {
+		s.ln = tls.NewListener(s.ln, cfg.TLS)
+	}
+	return s, nil
+}
+
+// Addr returns the bound address.
+func (s *Server) Addr() net.Addr {
+	if s.pc != nil {
+		return s.pc.LocalAddr()
+	}
+	return s.ln.Addr()
+}
+
+// Serve answers requests until Shutdown, then returns ErrServerClosed.
+func (s *Server) Serve() error {
+	if s.pc != nil {
+		log.Println("Modbus UDP listening on", s.cfg.Addr)
+
+		sess := newSession(s.pc)
+		if !s.track(sess) {
+			return ErrServerClosed
+		}
+		defer s.untrack(sess)
+
+		serveUDP(s.pc, s.filter, s.l, sess)
+		return ErrServerClosed
+	}
+
+	log.Println("Modbus TCP listening on", s.cfg.Addr, "max_connections =", s.maxConns, "framing =", s.framing, "tls =", s.cfg.TLS != nil, "pipeline_depth =", max(s.cfg.PipelineDepth, 1))
+
+	sem := make(chan struct{}, s.maxConns)
 
 	for {
-		conn, err := ln.Accept()
+		conn, err := s.ln.Accept()
 		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
 			continue
 		}
 
-		if filter.Enabled() {
-			host, _, err := net.SplitHostPort(conn.RemoteAddr().String())
-			if err != nil {
-				conn.Close()
-				continue
-			}
-
-			ip := net.ParseIP(host)
-			if !filter.Allowed(ip) {
-				conn.Close()
-				continue
-			}
+		if !remoteAllowed(s.filter, conn.RemoteAddr()) {
+			conn.Close()
+			continue
 		}
 
 		select {
@@ -62,9 +187,120 @@ func Start(
 			continue
 		}
 
+		sess := newSession(conn)
+		if !s.track(sess) {
+			conn.Close()
+			return ErrServerClosed
+		}
+
 		go func() {
 			defer func() { <-sem }()
-			handleConn(conn, resolve)
+			defer s.untrack(sess)
+			s.serveConn(conn, sess)
 		}()
 	}
 }
+
+// Shutdown stops accepting connections and waits for active ones to
+// finish their request in flight. Idle connections close at once.
+// When ctx expires first, remaining connections are closed and the
+// context error is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	if s.ln != nil {
+		s.ln.Close()
+	}
+	for sess := range s.sessions {
+		sess.closeIdle()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for sess := range s.sessions {
+			sess.forceClose()
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// track registers a session; it fails once shutdown has begun.
+func (s *Server) track(sess *session) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.sessions[sess] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *Server) untrack(sess *session) {
+	s.mu.Lock()
+	delete(s.sessions, sess)
+	s.mu.Unlock()
+	s.wg.Done()
+}
+
+// remoteAllowed applies the listener IP filter to a peer address.
+func remoteAllowed(filter *ipfilter.Filter, addr net.Addr) bool {
+	if !filter.Enabled() {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return false
+	}
+
+	return filter.Allowed(net.ParseIP(host))
+}
+
+// serveConn authorizes TLS clients by certificate role, then serves
+// the connection under the port's timeouts.
+func (s *Server) serveConn(conn net.Conn, sess *session) {
+	s.stats.active.Add(1)
+	defer s.stats.active.Add(-1)
+
+	l := s.l
+	if tc, ok := conn.(*tls.Conn); ok {
+		r, up, err := authorize(tc, s.cfg.Roles)
+		if err != nil {
+			log.Printf("modbus: rejecting %s: %v", conn.RemoteAddr(), err)
+			conn.Close()
+			return
+		}
+		l = l.withResolver(r, up)
+	}
+
+	if s.cfg.IdleTimeout > 0 || s.cfg.RequestTimeout > 0 {
+		tc := newTimedConn(conn, s.cfg.IdleTimeout, s.cfg.RequestTimeout, &s.stats)
+		sess.ready = tc.ready
+		conn = tc
+	}
+
+	if s.cfg.PipelineDepth > 1 {
+		servePipelined(conn, sess, s.cfg.PipelineDepth, l)
+		return
+	}
+	serveSession(conn, sess, s.framing, l)
+}
@@ -0,0 +1,84 @@
+package modbus
+
+import (
+	"io"
+	"log"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// benchServe measures FC 03 round trips over loopback TCP with up to
+// inflight requests outstanding on one connection. Each request waits
+// for delay in the resolver, like a slow gateway upstream.
+func benchServe(b *testing.B, cfg Config, inflight int, delay time.Duration) {
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	mem := newConformanceMemory()
+	resolve := func(unitID uint8, fc uint8) *core.Memory {
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		return mem
+	}
+
+	cfg.Addr = "127.0.0.1:0"
+	srv, err := Listen(cfg, resolve)
+	if err != nil {
+		b.Fatal(err)
+	}
+	go func() { _ = srv.Serve() }()
+	b.Cleanup(func() { _ = srv.Shutdown(b.Context()) })
+
+	c, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+
+	window := make(chan struct{}, inflight)
+	go func() {
+		for i := 0; i < b.N; i++ {
+			window <- struct{}{}
+			if _, err := c.Write(readHR0); err != nil {
+				return
+			}
+		}
+	}()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	resp := make([]byte, 11)
+	for i := 0; i < b.N; i++ {
+		if _, err := io.ReadFull(c, resp); err != nil {
+			b.Fatal(err)
+		}
+		<-window
+	}
+}
+
+func BenchmarkServe_Sequential(b *testing.B) {
+	benchServe(b, Config{}, 1, 0)
+}
+
+func BenchmarkServe_Burst(b *testing.B) {
+	// Client pipelines; the server answers one request at a time
+	benchServe(b, Config{}, 8, 0)
+}
+
+func BenchmarkServe_BurstPipelined(b *testing.B) {
+	benchServe(b, Config{PipelineDepth: 8}, 8, 0)
+}
+
+func BenchmarkServe_BurstSlowBackend(b *testing.B) {
+	benchServe(b, Config{}, 8, 200*time.Microsecond)
+}
+
+func BenchmarkServe_BurstSlowBackendPipelined(b *testing.B) {
+	benchServe(b, Config{PipelineDepth: 8}, 8, 200*time.Microsecond)
+}
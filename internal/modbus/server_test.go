@@ -0,0 +1,121 @@
+package modbus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// startServer serves unit 1 on loopback TCP. Each request waits for
+// delay inside the resolver to simulate a slow transaction.
+func startServer(t *testing.T, delay time.Duration) (*Server, chan error) {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	resolve := func(unitID uint8, fc uint8) *core.Memory {
+		time.Sleep(delay)
+		return mem
+	}
+
+	srv, err := Listen(Config{Addr: "127.0.0.1:0"}, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve() }()
+	return srv, served
+}
+
+func dialServer(t *testing.T, srv *Server) net.Conn {
+	t.Helper()
+
+	c, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+	_ = c.SetDeadline(time.Now().Add(2 * time.Second))
+	return c
+}
+
+var readHR0 = adu(1, 0x03, 0x00, 0x00, 0x00, 0x01)
+
+func TestServer_ShutdownClosesIdle(t *testing.T) {
+	srv, served := startServer(t, 0)
+	c := dialServer(t, srv)
+
+	if _, err := c.Write(readHR0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(c, make([]byte, 11)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Fatalf("expected ErrServerClosed, got %v", err)
+	}
+
+	// The idle connection is closed
+	if _, err := c.Read(make([]byte, 1)); err == nil {
+		t.Fatal("expected idle connection closed")
+	}
+
+	// No new connections
+	if c, err := net.Dial("tcp", srv.Addr().String()); err == nil {
+		c.Close()
+		t.Fatal("expected listener closed")
+	}
+}
+
+func TestServer_ShutdownDrainsInFlight(t *testing.T) {
+	srv, _ := startServer(t, 200*time.Millisecond)
+	c := dialServer(t, srv)
+
+	if _, err := c.Write(readHR0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan error, 1)
+	go func() { done <- srv.Shutdown(context.Background()) }()
+
+	// The transaction in flight completes
+	resp := make([]byte, 11)
+	if _, err := io.ReadFull(c, resp); err != nil {
+		t.Fatalf("in-flight response cut: %v", err)
+	}
+	if want := []byte{0x03, 0x02, 0x00, 0x0A}; !bytes.Equal(resp[7:], want) {
+		t.Fatalf("expected % X, got % X", want, resp[7:])
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestServer_ShutdownDeadline(t *testing.T) {
+	srv, _ := startServer(t, 500*time.Millisecond)
+	c := dialServer(t, srv)
+
+	if _, err := c.Write(readHR0); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package modbus
+
+import (
+	"io"
+	"sync"
+)
+
+// session guards the request cycle of one connection (or serial line,
+// or UDP socket) so that shutdown never cuts a transaction in half:
+// an idle session closes at once, a busy one right after its last
+// response (pipelined connections may have several in flight).
+type session struct {
+	c io.Closer
+
+	// ready, if set, runs before each request is read (idle timeout).
+	ready func()
+
+	mu       sync.Mutex
+	inFlight int
+	closed   bool
+}
+
+func newSession(c io.Closer) *session {
+	return &session{c: c}
+}
+
+// awaitRequest is called before reading the next request.
+func (s *session) awaitRequest() {
+	if s.ready != nil {
+		s.ready()
+	}
+}
+
+// begin marks a request in flight. It returns false once the session
+// is closing; the request must then be dropped.
+func (s *session) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.inFlight++
+	return true
+}
+
+// end marks a request done and completes a pending close.
+func (s *session) end() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inFlight--
+	if s.closed && s.inFlight == 0 {
+		_ = s.c.Close()
+	}
+}
+
+// closeIdle closes the session now if idle, otherwise after the
+// requests in flight.
+func (s *session) closeIdle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if s.inFlight == 0 {
+		_ = s.c.Close()
+	}
+}
+
+// forceClose closes the session even with a request in flight.
+func (s *session) forceClose() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	_ = s.c.Close()
+}
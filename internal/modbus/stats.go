@@ -0,0 +1,42 @@
+package modbus
+
+// Stats is a snapshot of one listener's counters.
+type Stats struct {
+	Addr      string `json:"addr"`
+	Transport string `json:"transport"`
+
+	// Connections (TCP only)
+	ActiveConnections int64  `json:"active_connections"`
+	IdleReaped        uint64 `json:"idle_reaped"`
+	RequestTimeouts   uint64 `json:"request_timeouts"`
+
+	// Communication counters as served by FC 08 (16-bit, wrapping)
+	BusMessages      uint16 `json:"bus_messages"`
+	BusCommErrors    uint16 `json:"bus_comm_errors"`
+	BusExceptions    uint16 `json:"bus_exceptions"`
+	ServerMessages   uint16 `json:"server_messages"`
+	ServerNoResponse uint16 `json:"server_no_response"`
+}
+
+// Stats returns the listener's counters.
+func (s *Server) Stats() Stats {
+	c := s.l.counters
+
+	transport := "tcp"
+	if s.pc != nil {
+		transport = "udp"
+	}
+
+	return Stats{
+		Addr:              s.Addr().String(),
+		Transport:         transport,
+		ActiveConnections: s.stats.active.Load(),
+		IdleReaped:        s.stats.idleReaped.Load(),
+		RequestTimeouts:   s.stats.requestTimeouts.Load(),
+		BusMessages:       load16(&c.busMessages),
+		BusCommErrors:     load16(&c.busCommErrors),
+		BusExceptions:     load16(&c.busExceptions),
+		ServerMessages:    load16(&c.serverMessages),
+		ServerNoResponse:  load16(&c.serverNoResponse),
+	}
+}
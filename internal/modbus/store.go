@@ -0,0 +1,71 @@
+package modbus
+
+import "modbus-memory-appliance/internal/core"
+
+// dataStore is what handlePDU reads and writes. Addresses are wire
+// (external) addresses; the store maps them onto core.Memory indexes.
+// Out-of-window access fails with core.ErrOutOfRange.
+type dataStore interface {
+	ReadCoils(addr, count int) ([]bool, error)
+	ReadDiscreteInputs(addr, count int) ([]bool, error)
+	ReadHoldingRegs(addr, count int) ([]uint16, error)
+	ReadInputRegs(addr, count int) ([]uint16, error)
+
+	WriteCoils(addr int, values []bool) error
+	WriteHoldingRegs(addr int, values []uint16) error
+	MaskWriteHoldingReg(addr int, andMask, orMask uint16) error
+	WriteReadHoldingRegs(writeAddr int, values []uint16, readAddr, count int) ([]uint16, error)
+
+	DeviceIdentity() map[uint8]string
+}
+
+// memoryStore addresses a memory directly through its AreaConfig.Start bases.
+type memoryStore struct {
+	mem *core.Memory
+}
+
+// writer attributes writes to Modbus in change events.
+func (s memoryStore) writer() core.Writer {
+	return s.mem.From(core.SourceModbus)
+}
+
+func (s memoryStore) ReadCoils(addr, count int) ([]bool, error) {
+	return s.mem.ReadCoils(s.mem.ToInternal(core.AreaCoils, addr), count)
+}
+
+func (s memoryStore) ReadDiscreteInputs(addr, count int) ([]bool, error) {
+	return s.mem.ReadDiscreteInputs(s.mem.ToInternal(core.AreaDiscreteInputs, addr), count)
+}
+
+func (s memoryStore) ReadHoldingRegs(addr, count int) ([]uint16, error) {
+	return s.mem.ReadHoldingRegs(s.mem.ToInternal(core.AreaHoldingRegs, addr), count)
+}
+
+func (s memoryStore) ReadInputRegs(addr, count int) ([]uint16, error) {
+	return s.mem.ReadInputRegs(s.mem.ToInternal(core.AreaInputRegs, addr), count)
+}
+
+func (s memoryStore) WriteCoils(addr int, values []bool) error {
+	return s.writer().WriteCoils(s.mem.ToInternal(core.AreaCoils, addr), values)
+}
+
+func (s memoryStore) WriteHoldingRegs(addr int, values []uint16) error {
+	return s.writer().WriteHoldingRegs(s.mem.ToInternal(core.AreaHoldingRegs, addr), values)
+}
+
+func (s memoryStore) MaskWriteHoldingReg(addr int, andMask, orMask uint16) error {
+	return s.writer().MaskWriteHoldingReg(s.mem.ToInternal(core.AreaHoldingRegs, addr), andMask, orMask)
+}
+
+func (s memoryStore) WriteReadHoldingRegs(writeAddr int, values []uint16, readAddr, count int) ([]uint16, error) {
+	return s.writer().WriteReadHoldingRegs(
+		s.mem.ToInternal(core.AreaHoldingRegs, writeAddr),
+		values,
+		s.mem.ToInternal(core.AreaHoldingRegs, readAddr),
+		count,
+	)
+}
+
+func (s memoryStore) DeviceIdentity() map[uint8]string {
+	return s.mem.DeviceIdentity()
+}
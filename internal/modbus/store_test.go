@@ -0,0 +1,70 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestAddressBase_HoldingWindow(t *testing.T) {
+	// holding_registers: start 40000, size 8
+	mem := core.NewMemory(8, 8, 8, 8)
+	mem.SetAddressBase(core.AreaHoldingRegs, 40000)
+	_ = mem.WriteHoldingRegs(0, []uint16{0x0102, 0x0304})
+
+	cases := []struct {
+		name string
+		pdu  PDU
+		want []byte
+	}{
+		{
+			name: "first register",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x9C, 0x40, 0x00, 0x02}}, // 40000
+			want: []byte{0x03, 0x04, 0x01, 0x02, 0x03, 0x04},
+		},
+		{
+			name: "below window",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x9C, 0x3F, 0x00, 0x01}}, // 39999
+			want: []byte{0x83, 0x02},
+		},
+		{
+			name: "zero no longer maps to index 0",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}},
+			want: []byte{0x83, 0x02},
+		},
+		{
+			name: "past window",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x9C, 0x47, 0x00, 0x02}}, // 40007..40008
+			want: []byte{0x83, 0x02},
+		},
+		{
+			name: "write last register",
+			pdu:  PDU{Function: 0x06, Data: []byte{0x9C, 0x47, 0x00, 0x2A}}, // 40007
+			want: []byte{0x06, 0x9C, 0x47, 0x00, 0x2A},
+		},
+	}
+
+	for _, tc := range cases {
+		if got := handlePDU(tc.pdu, memoryStore{mem: mem}); !bytes.Equal(got, tc.want) {
+			t.Fatalf("%s: expected % X, got % X", tc.name, tc.want, got)
+		}
+	}
+
+	vals, _ := mem.ReadHoldingRegs(7, 1)
+	if vals[0] != 0x2A {
+		t.Fatalf("expected internal index 7 written, got %d", vals[0])
+	}
+}
+
+func TestAddressBase_PerArea(t *testing.T) {
+	// A base on holding registers must not shift coils
+	mem := core.NewMemory(8, 8, 8, 8)
+	mem.SetAddressBase(core.AreaHoldingRegs, 100)
+	_ = mem.WriteCoils(0, []bool{true})
+
+	got := handlePDU(PDU{Function: 0x01, Data: []byte{0x00, 0x00, 0x00, 0x01}}, memoryStore{mem: mem})
+	if want := []byte{0x01, 0x01, 0x01}; !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
@@ -0,0 +1,113 @@
+package modbus
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// errIdleTimeout: no request started within the idle timeout.
+	errIdleTimeout = errors.New("modbus: idle timeout")
+
+	// errRequestTimeout: a started request was not completed in time.
+	errRequestTimeout = errors.New("modbus: request timeout")
+)
+
+// connStats counts connection lifecycle events of one listener.
+// Unlike commCounters they are not Modbus counters and FC 08 does not
+// clear them.
+type connStats struct {
+	active          atomic.Int64
+	idleReaped      atomic.Uint64
+	requestTimeouts atomic.Uint64
+}
+
+// timedConn enforces the idle and request timeouts of a port. Codecs
+// may still set their own, shorter read deadlines (e.g. the RTU gap);
+// expiry of those is reported to the codec unchanged.
+//
+// Only the connection's serving goroutine may use it.
+type timedConn struct {
+	net.Conn
+	idle    time.Duration // 0 = wait forever for the next request
+	request time.Duration // 0 = no limit once a request started
+	stats   *connStats
+
+	inRequest     bool
+	phaseEnd      time.Time // end of the idle or request phase
+	codecDeadline time.Time
+}
+
+func newTimedConn(conn net.Conn, idle, request time.Duration, stats *connStats) *timedConn {
+	c := &timedConn{Conn: conn, idle: idle, request: request, stats: stats}
+	c.ready()
+	return c
+}
+
+// ready starts waiting for the next request.
+func (c *timedConn) ready() {
+	c.inRequest = false
+	c.phaseEnd = deadlineAfter(c.idle)
+}
+
+// SetReadDeadline records the codec's deadline; Read combines it with
+// the phase deadline.
+func (c *timedConn) SetReadDeadline(t time.Time) error {
+	c.codecDeadline = t
+	return nil
+}
+
+func (c *timedConn) Read(p []byte) (int, error) {
+	deadline, ours := c.codecDeadline, false
+	if !c.phaseEnd.IsZero() && (deadline.IsZero() || c.phaseEnd.Before(deadline)) {
+		deadline, ours = c.phaseEnd, true
+	}
+
+	if err := c.Conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+
+	wasIdle := !c.inRequest
+	n, err := c.Conn.Read(p)
+
+	// First byte of a request: the request timeout starts
+	if n > 0 && wasIdle {
+		c.inRequest = true
+		c.phaseEnd = deadlineAfter(c.request)
+	}
+
+	if ours && errors.Is(err, os.ErrDeadlineExceeded) {
+		if wasIdle {
+			c.stats.idleReaped.Add(1)
+			return n, errIdleTimeout
+		}
+		c.stats.requestTimeouts.Add(1)
+		return n, errRequestTimeout
+	}
+	return n, err
+}
+
+// Write bounds the response by the request timeout.
+func (c *timedConn) Write(p []byte) (int, error) {
+	if c.request > 0 {
+		if err := c.Conn.SetWriteDeadline(time.Now().Add(c.request)); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := c.Conn.Write(p)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		c.stats.requestTimeouts.Add(1)
+	}
+	return n, err
+}
+
+func deadlineAfter(d time.Duration) time.Time {
+	if d <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(d)
+}
@@ -0,0 +1,109 @@
+package modbus
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// startTimedServer serves unit 1 on loopback TCP with cfg's timeouts.
+func startTimedServer(t *testing.T, cfg Config) *Server {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	resolve := func(unitID uint8, fc uint8) *core.Memory { return mem }
+
+	cfg.Addr = "127.0.0.1:0"
+	srv, err := Listen(cfg, resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve() }()
+	t.Cleanup(func() { _ = srv.Shutdown(t.Context()) })
+	return srv
+}
+
+// expectClosed waits for the server to close c.
+func expectClosed(t *testing.T, c io.Reader) {
+	t.Helper()
+
+	if _, err := c.Read(make([]byte, 1)); err != io.EOF {
+		t.Fatalf("expected connection closed by server, got %v", err)
+	}
+}
+
+func TestTimeouts_IdleReaped(t *testing.T) {
+	srv := startTimedServer(t, Config{IdleTimeout: 100 * time.Millisecond})
+	c := dialServer(t, srv)
+
+	expectClosed(t, c)
+
+	if got := srv.Stats().IdleReaped; got != 1 {
+		t.Fatalf("expected 1 idle connection reaped, got %d", got)
+	}
+}
+
+func TestTimeouts_ActiveNotReaped(t *testing.T) {
+	srv := startTimedServer(t, Config{IdleTimeout: 150 * time.Millisecond})
+	c := dialServer(t, srv)
+
+	// Total session time well past the idle timeout
+	for range 4 {
+		time.Sleep(75 * time.Millisecond)
+		if _, err := c.Write(readHR0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadFull(c, make([]byte, 11)); err != nil {
+			t.Fatalf("expected active connection served, got %v", err)
+		}
+	}
+
+	if got := srv.Stats().IdleReaped; got != 0 {
+		t.Fatalf("expected no idle reap, got %d", got)
+	}
+}
+
+func TestTimeouts_PartialRequest(t *testing.T) {
+	srv := startTimedServer(t, Config{
+		IdleTimeout:    time.Second,
+		RequestTimeout: 100 * time.Millisecond,
+	})
+	c := dialServer(t, srv)
+
+	// Header only: the PDU never arrives
+	if _, err := c.Write(readHR0[:7]); err != nil {
+		t.Fatal(err)
+	}
+	expectClosed(t, c)
+
+	st := srv.Stats()
+	if st.RequestTimeouts != 1 || st.IdleReaped != 0 {
+		t.Fatalf("expected 1 request timeout, got %+v", st)
+	}
+}
+
+func TestTimeouts_IdleReapedRTU(t *testing.T) {
+	// The RTU codec sets its own read deadlines; the idle timeout
+	// must still apply between frames.
+	srv := startTimedServer(t, Config{
+		Framing:     FramingRTU,
+		IdleTimeout: 100 * time.Millisecond,
+	})
+	c := dialServer(t, srv)
+
+	req := withCRC(0x01, 0x03, 0x00, 0x00, 0x00, 0x01)
+	if _, err := c.Write(req); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(c, make([]byte, 7)); err != nil {
+		t.Fatalf("expected rtu response, got %v", err)
+	}
+
+	expectClosed(t, c)
+
+	if got := srv.Stats().IdleReaped; got != 1 {
+		t.Fatalf("expected 1 idle connection reaped, got %d", got)
+	}
+}
@@ -0,0 +1,235 @@
+package modbus
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+type testPKI struct {
+	ca    *x509.Certificate
+	caKey *ecdsa.PrivateKey
+	pool  *x509.CertPool
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return &testPKI{ca: ca, caKey: key, pool: pool}
+}
+
+// issue signs a leaf certificate. A non-empty role is embedded in the
+// Modbus role extension.
+func (p *testPKI) issue(t *testing.T, cn string, role string, usage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+
+	if role != "" {
+		val, err := asn1.MarshalWithParams(role, "utf8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		tmpl.ExtraExtensions = []pkix.Extension{{Id: RoleOID, Value: val}}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, p.ca, &key.PublicKey, p.caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startTLSConn serves one TLS connection the way the listener does and
+// returns the connected client.
+func startTLSConn(t *testing.T, pki *testPKI, client tls.Certificate) *tls.Conn {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	roles := func(role string) (MemoryResolver, UpstreamResolver) {
+		switch role {
+		case "operator":
+			return func(unitID uint8, fc uint8) *core.Memory { return mem }, nil
+		case "viewer":
+			return func(unitID uint8, fc uint8) *core.Memory {
+				if fc != 0x03 {
+					return nil
+				}
+				return mem
+			}, nil
+		}
+		return nil, nil
+	}
+
+	serverCfg := &tls.Config{
+		Certificates: []tls.Certificate{pki.issue(t, "mma", "", x509.ExtKeyUsageServerAuth)},
+		ClientCAs:    pki.pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	// Loopback TCP rather than net.Pipe: TLS alerts need a buffered transport
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		s, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		tc := tls.Server(s, serverCfg)
+		srv := &Server{cfg: Config{Roles: roles}, framing: FramingMBAP, l: newListener(nil, Policy{})}
+		srv.serveConn(tc, newSession(tc))
+	}()
+
+	c, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn := tls.Client(c, &tls.Config{
+		Certificates: []tls.Certificate{client},
+		RootCAs:      pki.pool,
+		ServerName:   "mma",
+	})
+	t.Cleanup(func() { conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(2 * time.Second))
+	return conn
+}
+
+func tlsExchange(conn *tls.Conn, req []byte) ([]byte, error) {
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	hdr := make([]byte, 7)
+	if _, err := io.ReadFull(conn, hdr); err != nil {
+		return nil, err
+	}
+	body := make([]byte, int(hdr[4])<<8|int(hdr[5])-1)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	return append(hdr, body...), nil
+}
+
+func TestClientRole(t *testing.T) {
+	pki := newTestPKI(t)
+
+	withRole := pki.issue(t, "hmi", "operator", x509.ExtKeyUsageClientAuth)
+	cert, _ := x509.ParseCertificate(withRole.Certificate[0])
+	if role, err := clientRole(cert); err != nil || role != "operator" {
+		t.Fatalf("expected role operator, got %q (%v)", role, err)
+	}
+
+	noRole := pki.issue(t, "hmi", "", x509.ExtKeyUsageClientAuth)
+	cert, _ = x509.ParseCertificate(noRole.Certificate[0])
+	if _, err := clientRole(cert); err != errNoRole {
+		t.Fatalf("expected errNoRole, got %v", err)
+	}
+}
+
+func TestTLS_RoleGrantsAccess(t *testing.T) {
+	pki := newTestPKI(t)
+	conn := startTLSConn(t, pki, pki.issue(t, "hmi", "operator", x509.ExtKeyUsageClientAuth))
+
+	got, err := tlsExchange(conn, adu(1, 0x06, 0x00, 0x01, 0x00, 0x2A))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := adu(1, 0x06, 0x00, 0x01, 0x00, 0x2A); !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestTLS_RoleRestrictsFunctionCodes(t *testing.T) {
+	pki := newTestPKI(t)
+	conn := startTLSConn(t, pki, pki.issue(t, "scada", "viewer", x509.ExtKeyUsageClientAuth))
+
+	got, err := tlsExchange(conn, adu(1, 0x03, 0x00, 0x00, 0x00, 0x01))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := adu(1, 0x03, 0x02, 0x00, 0x0A); !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+
+	got, err = tlsExchange(conn, adu(1, 0x06, 0x00, 0x01, 0x00, 0x2A))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := adu(1, 0x86, 0x02); !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestTLS_RejectsUnauthorizedClients(t *testing.T) {
+	pki := newTestPKI(t)
+
+	cases := map[string]tls.Certificate{
+		"no role":      pki.issue(t, "hmi", "", x509.ExtKeyUsageClientAuth),
+		"unknown role": pki.issue(t, "hmi", "guest", x509.ExtKeyUsageClientAuth),
+		"foreign CA":   newTestPKI(t).issue(t, "hmi", "operator", x509.ExtKeyUsageClientAuth),
+	}
+
+	for name, cert := range cases {
+		conn := startTLSConn(t, pki, cert)
+		if _, err := tlsExchange(conn, adu(1, 0x03, 0x00, 0x00, 0x00, 0x01)); err == nil {
+			t.Fatalf("%s: expected connection to be rejected", name)
+		}
+	}
+}
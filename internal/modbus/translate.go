@@ -1,5 +0,0 @@
-package modbus
-
-func extToInternal(addr uint16) int {
-	return int(addr)
-}
@@ -0,0 +1,103 @@
+package modbus
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"net"
+
+	"modbus-memory-appliance/internal/modbus/ipfilter"
+)
+
+// Transport selects the IP transport of a listener.
+type Transport string
+
+const (
+	TransportTCP Transport = "tcp" // default
+	TransportUDP Transport = "udp"
+)
+
+// maxMBAPADU is the largest MBAP frame: 6 header bytes + MBAP length.
+const maxMBAPADU = 6 + maxMBAPLength
+
+// serveUDP answers MBAP datagrams until pc is closed.
+// Datagrams are handled in order; there is no connection state.
+// sess wraps pc so shutdown lets the datagram in hand be answered.
+func serveUDP(
+	pc net.PacketConn,
+	filter *ipfilter.Filter,
+	l *listener,
+	sess *session,
+) {
+	// One spare byte so oversized datagrams are detected, not truncated
+	buf := make([]byte, maxMBAPADU+1)
+
+	for {
+		n, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+
+		if !remoteAllowed(filter, addr) {
+			continue
+		}
+
+		if !sess.begin() {
+			return
+		}
+
+		resp := handleDatagram(buf[:n], addr.String(), l)
+		if resp != nil {
+			if _, err := pc.WriteTo(resp, addr); err != nil {
+				l.counters.serverNoResponse.Add(1)
+			}
+		}
+
+		sess.end()
+	}
+}
+
+// handleDatagram answers one MBAP datagram. A datagram must carry exactly
+// one complete ADU; anything else is dropped without a reply.
+func handleDatagram(
+	adu []byte,
+	peer string,
+	l *listener,
+) (out []byte) {
+	// Same guarantee as the stream path: a bad datagram never stops the listener
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("modbus: dropping datagram from %s after panic: %v", peer, r)
+			out = nil
+		}
+	}()
+
+	in := bytes.NewReader(adu)
+	var resp bytes.Buffer
+
+	codec := newMBAPCodec(struct {
+		io.Reader
+		io.Writer
+	}{in, &resp})
+
+	unitID, pdu, err := codec.readRequest()
+	if err != nil || in.Len() != 0 {
+		l.counters.busCommErrors.Add(1)
+		return nil
+	}
+
+	reply := serveRequest(unitID, pdu, l)
+	if reply == nil {
+		// Broadcast: no reply
+		return nil
+	}
+
+	if err := codec.writeResponse(unitID, reply); err != nil {
+		return nil
+	}
+	return resp.Bytes()
+}
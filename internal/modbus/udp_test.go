@@ -0,0 +1,118 @@
+package modbus
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/modbus/ipfilter"
+)
+
+func startUDPListener(t *testing.T, deny []string) (net.Conn, *commCounters) {
+	t.Helper()
+
+	mem := newConformanceMemory()
+	filter, err := ipfilter.Compile(nil, deny)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	l := newListener(unitOneResolver(mem), Policy{})
+	go serveUDP(pc, filter, l, newSession(pc))
+	counters := l.counters
+
+	client, err := net.Dial("udp", pc.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client, counters
+}
+
+func udpExchange(t *testing.T, client net.Conn, req []byte) []byte {
+	t.Helper()
+
+	if _, err := client.Write(req); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = client.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	buf := make([]byte, maxMBAPADU)
+	n, err := client.Read(buf)
+	if err != nil {
+		return nil
+	}
+	return buf[:n]
+}
+
+func TestUDP_ReadHoldingRegisters(t *testing.T) {
+	client, _ := startUDPListener(t, nil)
+
+	// Transaction ID is echoed per datagram
+	req := adu(1, 0x03, 0x00, 0x00, 0x00, 0x02)
+	req[0], req[1] = 0x12, 0x34
+	want := adu(1, 0x03, 0x04, 0x00, 0x0A, 0x00, 0x0B)
+	want[0], want[1] = 0x12, 0x34
+
+	got := udpExchange(t, client, req)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestUDP_UnknownUnitReturnsException(t *testing.T) {
+	client, _ := startUDPListener(t, nil)
+
+	got := udpExchange(t, client, adu(9, 0x03, 0x00, 0x00, 0x00, 0x01))
+	want := adu(9, 0x83, 0x02)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestUDP_MalformedDatagramDropped(t *testing.T) {
+	client, counters := startUDPListener(t, nil)
+
+	// MBAP length claims more bytes than the datagram carries
+	bad := adu(1, 0x03, 0x00, 0x00, 0x00, 0x01)
+	bad[5] += 4
+	if got := udpExchange(t, client, bad); got != nil {
+		t.Fatalf("expected no reply, got % X", got)
+	}
+
+	// Trailing bytes after the ADU
+	trailing := append(adu(1, 0x03, 0x00, 0x00, 0x00, 0x01), 0xFF)
+	if got := udpExchange(t, client, trailing); got != nil {
+		t.Fatalf("expected no reply, got % X", got)
+	}
+
+	if n := counters.busCommErrors.Load(); n != 2 {
+		t.Fatalf("expected 2 comm errors, got %d", n)
+	}
+
+	// The listener keeps serving
+	got := udpExchange(t, client, adu(1, 0x03, 0x00, 0x00, 0x00, 0x01))
+	want := adu(1, 0x03, 0x02, 0x00, 0x0A)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("expected % X, got % X", want, got)
+	}
+}
+
+func TestUDP_IPFilterDenies(t *testing.T) {
+	client, counters := startUDPListener(t, []string{"127.0.0.0/8"})
+
+	if got := udpExchange(t, client, adu(1, 0x03, 0x00, 0x00, 0x00, 0x01)); got != nil {
+		t.Fatalf("expected no reply, got % X", got)
+	}
+	if n := counters.busMessages.Load(); n != 0 {
+		t.Fatalf("expected denied datagram not counted, got %d", n)
+	}
+}
@@ -0,0 +1,222 @@
+package modbus
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// errUpstreamBusy means every pooled connection stayed in use
+	// until the request timed out.
+	errUpstreamBusy = errors.New("modbus: upstream connection pool exhausted")
+
+	// errUpstreamNoResponse means the device could not be reached or
+	// did not answer in time.
+	errUpstreamNoResponse = errors.New("modbus: upstream device failed to respond")
+)
+
+// Upstream is a remote Modbus TCP device served in gateway mode.
+// Connections are opened on demand, up to the pool size, and reused.
+// A connection is closed after any error, and every connection once
+// the pool is closed.
+type Upstream struct {
+	addr    string
+	timeout time.Duration
+
+	idle  chan net.Conn // open connections not in use
+	slots chan struct{} // one token per open connection
+
+	tid atomic.Uint32
+
+	mu     sync.Mutex // guards closed against returns to idle
+	closed bool
+}
+
+// NewUpstream creates the pool for one device. timeout bounds a whole
+// request, connection setup included.
+func NewUpstream(addr string, poolSize int, timeout time.Duration) *Upstream {
+	return &Upstream{
+		addr:    addr,
+		timeout: timeout,
+		idle:    make(chan net.Conn, poolSize),
+		slots:   make(chan struct{}, poolSize),
+	}
+}
+
+// Do sends one request PDU to unitID on the device and returns the
+// response PDU (which may be a Modbus exception from the device).
+func (u *Upstream) Do(unitID uint8, pdu PDU) ([]byte, error) {
+	deadline := time.Now().Add(u.timeout)
+
+	for {
+		if u.isClosed() {
+			return nil, errUpstreamNoResponse
+		}
+
+		conn, reused, err := u.acquire(deadline)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, sent, err := u.exchange(conn, deadline, unitID, pdu)
+		if err == nil {
+			u.release(conn)
+			return resp, nil
+		}
+		u.discard(conn)
+
+		// Once the request is written the device may have executed it,
+		// and a retry could apply a write twice. Only a request that
+		// never reached a stale pooled connection is sent again.
+		if !reused || sent || time.Now().After(deadline) {
+			return nil, fmt.Errorf("%w: %v", errUpstreamNoResponse, err)
+		}
+	}
+}
+
+// acquire returns a live idle connection, or dials a new one if the
+// pool has room. reused reports whether the connection was pooled.
+// Idle connections the device dropped are discarded on the way.
+func (u *Upstream) acquire(deadline time.Time) (conn net.Conn, reused bool, err error) {
+	for {
+		conn, reused, err = u.take(deadline)
+		if err != nil || !reused || alive(conn) {
+			return conn, reused, err
+		}
+		u.discard(conn)
+	}
+}
+
+// probeWait bounds the liveness read on an idle connection. It must be
+// in the future: a read past its deadline fails before looking at the
+// socket.
+const probeWait = 100 * time.Microsecond
+
+// alive reports whether an idle connection is still open. A device
+// that dropped it has left EOF (or stray data) to read; a live one
+// has nothing, so the read times out.
+func alive(conn net.Conn) bool {
+	if err := conn.SetReadDeadline(time.Now().Add(probeWait)); err != nil {
+		return false
+	}
+
+	var b [1]byte
+	_, err := conn.Read(b[:])
+
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// take returns an idle connection, or dials a new one if the pool has
+// room.
+func (u *Upstream) take(deadline time.Time) (conn net.Conn, reused bool, err error) {
+	select {
+	case c := <-u.idle:
+		return c, true, nil
+	default:
+	}
+
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case c := <-u.idle:
+		return c, true, nil
+	case u.slots <- struct{}{}:
+		c, err := net.DialTimeout("tcp", u.addr, time.Until(deadline))
+		if err != nil {
+			<-u.slots
+			return nil, false, fmt.Errorf("%w: %v", errUpstreamNoResponse, err)
+		}
+		return c, false, nil
+	case <-timer.C:
+		return nil, false, errUpstreamBusy
+	}
+}
+
+func (u *Upstream) discard(conn net.Conn) {
+	conn.Close()
+	<-u.slots
+}
+
+// release returns a connection to the pool, or closes it once the pool
+// is closed.
+func (u *Upstream) release(conn net.Conn) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.closed {
+		u.discard(conn)
+		return
+	}
+	u.idle <- conn
+}
+
+func (u *Upstream) isClosed() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.closed
+}
+
+// Close closes the idle connections. Connections in use are closed
+// when their request completes, and later requests fail.
+func (u *Upstream) Close() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.closed = true
+	for {
+		select {
+		case conn := <-u.idle:
+			u.discard(conn)
+		default:
+			return nil
+		}
+	}
+}
+
+// exchange runs one MBAP transaction. Requests carry the pool's own
+// transaction IDs; the client's ID is restored by its codec. sent
+// reports whether the request was written to the device.
+func (u *Upstream) exchange(conn net.Conn, deadline time.Time, unitID uint8, pdu PDU) (resp []byte, sent bool, err error) {
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, false, err
+	}
+
+	tid := uint16(u.tid.Add(1))
+
+	// Header and PDU in one write
+	frame := make([]byte, 8+len(pdu.Data))
+	binary.BigEndian.PutUint16(frame[0:2], tid)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(2+len(pdu.Data)))
+	frame[6] = unitID
+	frame[7] = pdu.Function
+	copy(frame[8:], pdu.Data)
+
+	if _, err := conn.Write(frame); err != nil {
+		return nil, false, err
+	}
+
+	mbap, err := readMBAP(conn)
+	if err != nil {
+		return nil, true, err
+	}
+	if mbap.ProtocolID != 0 || mbap.TransactionID != tid {
+		return nil, true, errInvalidFrame
+	}
+
+	rp, err := readPDU(conn, mbap.Length)
+	if err != nil {
+		return nil, true, err
+	}
+	if rp.Function&0x7F != pdu.Function {
+		return nil, true, errInvalidFrame
+	}
+
+	return append([]byte{rp.Function}, rp.Data...), true, nil
+}
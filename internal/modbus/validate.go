@@ -0,0 +1,141 @@
+package modbus
+
+import "encoding/binary"
+
+// Quantity limits from the Modbus Application Protocol specification.
+const (
+	maxReadBits       = 2000 // FC 01, 02
+	maxReadRegisters  = 125  // FC 03, 04, 23 (read part)
+	maxWriteBits      = 1968 // FC 15
+	maxWriteRegisters = 123  // FC 16
+	maxRWWriteRegs    = 121  // FC 23 (write part)
+)
+
+// Exception codes returned by validation.
+const (
+	excIllegalFunction  = 0x01
+	excIllegalDataValue = 0x03
+)
+
+// validateRequest checks PDU structure and quantity limits before any
+// memory access. It returns 0 for a well-formed request, otherwise the
+// exception code to answer with.
+//
+// Order follows the spec state diagrams:
+// function code (0x01) -> length / quantity (0x03).
+// Address checks (0x02) are done by memory bounds.
+func validateRequest(pdu PDU) uint8 {
+	d := pdu.Data
+
+	switch pdu.Function {
+
+	case 0x01, 0x02: // Read Coils / Discrete Inputs
+		if len(d) != 4 {
+			return excIllegalDataValue
+		}
+		return checkQuantity(d[2:4], maxReadBits)
+
+	case 0x03, 0x04: // Read Holding / Input Registers
+		if len(d) != 4 {
+			return excIllegalDataValue
+		}
+		return checkQuantity(d[2:4], maxReadRegisters)
+
+	case 0x05: // Write Single Coil
+		if len(d) != 4 {
+			return excIllegalDataValue
+		}
+		v := binary.BigEndian.Uint16(d[2:4])
+		if v != 0x0000 && v != 0xFF00 {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x06: // Write Single Register
+		if len(d) != 4 {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x0F: // Write Multiple Coils
+		if len(d) < 5 {
+			return excIllegalDataValue
+		}
+		if code := checkQuantity(d[2:4], maxWriteBits); code != 0 {
+			return code
+		}
+		count := int(binary.BigEndian.Uint16(d[2:4]))
+		byteCount := int(d[4])
+		if byteCount != (count+7)/8 || len(d) != 5+byteCount {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x10: // Write Multiple Registers
+		if len(d) < 5 {
+			return excIllegalDataValue
+		}
+		if code := checkQuantity(d[2:4], maxWriteRegisters); code != 0 {
+			return code
+		}
+		count := int(binary.BigEndian.Uint16(d[2:4]))
+		byteCount := int(d[4])
+		if byteCount != count*2 || len(d) != 5+byteCount {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x16: // Mask Write Register
+		if len(d) != 6 {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x17: // Read/Write Multiple Registers
+		if len(d) < 9 {
+			return excIllegalDataValue
+		}
+		if code := checkQuantity(d[2:4], maxReadRegisters); code != 0 {
+			return code
+		}
+		if code := checkQuantity(d[6:8], maxRWWriteRegs); code != 0 {
+			return code
+		}
+		writeCount := int(binary.BigEndian.Uint16(d[6:8]))
+		byteCount := int(d[8])
+		if byteCount != writeCount*2 || len(d) != 9+byteCount {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x08: // Diagnostics (sub-function + data)
+		if len(d) < 4 {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x0B, 0x11: // Get Comm Event Counter, Report Server ID
+		if len(d) != 0 {
+			return excIllegalDataValue
+		}
+		return 0
+
+	case 0x2B: // Encapsulated Interface Transport
+		if len(d) < 1 {
+			return excIllegalDataValue
+		}
+		return 0
+
+	default:
+		return excIllegalFunction
+	}
+}
+
+// checkQuantity enforces 1 <= quantity <= max.
+func checkQuantity(b []byte, max int) uint8 {
+	q := int(binary.BigEndian.Uint16(b))
+	if q < 1 || q > max {
+		return excIllegalDataValue
+	}
+	return 0
+}
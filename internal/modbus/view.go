@@ -0,0 +1,181 @@
+package modbus
+
+import "modbus-memory-appliance/internal/core"
+
+// Window maps an external address window of one area onto a memory.
+// Target may differ from Area to mirror data (e.g. input registers
+// showing a holding-register range).
+type Window struct {
+	Area   core.Area // external area
+	Start  int       // first external address
+	Length int
+
+	Target core.Area // memory area
+	Offset int       // zero-based index in Target
+}
+
+// View remaps a port's address windows onto the memory it resolves to.
+// An area with windows is reachable only through them; areas without
+// windows keep their AreaConfig.Start addressing.
+type View struct {
+	windows [4][]Window
+}
+
+// NewView builds a view. Windows are validated by config.
+func NewView(windows []Window) *View {
+	v := &View{}
+	for _, w := range windows {
+		v.windows[w.Area] = append(v.windows[w.Area], w)
+	}
+	return v
+}
+
+// bind presents mem through the view.
+func (v *View) bind(mem *core.Memory) dataStore {
+	return viewStore{view: v, direct: memoryStore{mem: mem}}
+}
+
+// viewStore is a memory seen through a View. A request must fall inside
+// a single window so that it stays one atomic memory call.
+type viewStore struct {
+	view   *View
+	direct memoryStore
+}
+
+// locate maps an external range to (target area, internal index).
+// ok is false for areas without windows.
+func (s viewStore) locate(area core.Area, addr, count int) (core.Area, int, bool, error) {
+	windows := s.view.windows[area]
+	if len(windows) == 0 {
+		return 0, 0, false, nil
+	}
+
+	for _, w := range windows {
+		if addr >= w.Start && addr+count <= w.Start+w.Length {
+			return w.Target, w.Offset + addr - w.Start, true, nil
+		}
+	}
+	return 0, 0, true, core.ErrOutOfRange
+}
+
+func (s viewStore) readBools(area core.Area, addr, count int) ([]bool, error) {
+	target, idx, mapped, err := s.locate(area, addr, count)
+	if err != nil {
+		return nil, err
+	}
+	if !mapped {
+		if area == core.AreaCoils {
+			return s.direct.ReadCoils(addr, count)
+		}
+		return s.direct.ReadDiscreteInputs(addr, count)
+	}
+
+	mem := s.direct.mem
+	switch target {
+	case core.AreaCoils:
+		return mem.ReadCoils(idx, count)
+	case core.AreaDiscreteInputs:
+		return mem.ReadDiscreteInputs(idx, count)
+	}
+	return nil, core.ErrOutOfRange
+}
+
+func (s viewStore) readRegs(area core.Area, addr, count int) ([]uint16, error) {
+	target, idx, mapped, err := s.locate(area, addr, count)
+	if err != nil {
+		return nil, err
+	}
+	if !mapped {
+		if area == core.AreaHoldingRegs {
+			return s.direct.ReadHoldingRegs(addr, count)
+		}
+		return s.direct.ReadInputRegs(addr, count)
+	}
+
+	mem := s.direct.mem
+	switch target {
+	case core.AreaHoldingRegs:
+		return mem.ReadHoldingRegs(idx, count)
+	case core.AreaInputRegs:
+		return mem.ReadInputRegs(idx, count)
+	}
+	return nil, core.ErrOutOfRange
+}
+
+// holdingIndex maps a holding-register write range. Writable windows
+// always target holding registers (enforced by config).
+func (s viewStore) holdingIndex(addr, count int) (int, error) {
+	target, idx, mapped, err := s.locate(core.AreaHoldingRegs, addr, count)
+	if err != nil {
+		return 0, err
+	}
+	if !mapped {
+		return s.direct.mem.ToInternal(core.AreaHoldingRegs, addr), nil
+	}
+	if target != core.AreaHoldingRegs {
+		return 0, core.ErrOutOfRange
+	}
+	return idx, nil
+}
+
+func (s viewStore) ReadCoils(addr, count int) ([]bool, error) {
+	return s.readBools(core.AreaCoils, addr, count)
+}
+
+func (s viewStore) ReadDiscreteInputs(addr, count int) ([]bool, error) {
+	return s.readBools(core.AreaDiscreteInputs, addr, count)
+}
+
+func (s viewStore) ReadHoldingRegs(addr, count int) ([]uint16, error) {
+	return s.readRegs(core.AreaHoldingRegs, addr, count)
+}
+
+func (s viewStore) ReadInputRegs(addr, count int) ([]uint16, error) {
+	return s.readRegs(core.AreaInputRegs, addr, count)
+}
+
+func (s viewStore) WriteCoils(addr int, values []bool) error {
+	target, idx, mapped, err := s.locate(core.AreaCoils, addr, len(values))
+	if err != nil {
+		return err
+	}
+	if !mapped {
+		return s.direct.WriteCoils(addr, values)
+	}
+	if target != core.AreaCoils {
+		return core.ErrOutOfRange
+	}
+	return s.direct.writer().WriteCoils(idx, values)
+}
+
+func (s viewStore) WriteHoldingRegs(addr int, values []uint16) error {
+	idx, err := s.holdingIndex(addr, len(values))
+	if err != nil {
+		return err
+	}
+	return s.direct.writer().WriteHoldingRegs(idx, values)
+}
+
+func (s viewStore) MaskWriteHoldingReg(addr int, andMask, orMask uint16) error {
+	idx, err := s.holdingIndex(addr, 1)
+	if err != nil {
+		return err
+	}
+	return s.direct.writer().MaskWriteHoldingReg(idx, andMask, orMask)
+}
+
+func (s viewStore) WriteReadHoldingRegs(writeAddr int, values []uint16, readAddr, count int) ([]uint16, error) {
+	w, err := s.holdingIndex(writeAddr, len(values))
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.holdingIndex(readAddr, count)
+	if err != nil {
+		return nil, err
+	}
+	return s.direct.writer().WriteReadHoldingRegs(w, values, r, count)
+}
+
+func (s viewStore) DeviceIdentity() map[uint8]string {
+	return s.direct.DeviceIdentity()
+}
@@ -0,0 +1,78 @@
+package modbus
+
+import (
+	"bytes"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestView_RemapsWindows(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	mem.HoldingRegs[0] = 0x1111
+	mem.HoldingRegs[4] = 0x4444
+	mem.Coils[1] = true
+	resolve := func(unitID uint8, fc uint8) *core.Memory { return mem }
+
+	view := NewView([]Window{
+		// HR 3000..3003 -> HR 0..3
+		{Area: core.AreaHoldingRegs, Start: 3000, Length: 4, Target: core.AreaHoldingRegs, Offset: 0},
+		// IR 100..103 mirror HR 4..7
+		{Area: core.AreaInputRegs, Start: 100, Length: 4, Target: core.AreaHoldingRegs, Offset: 4},
+		// IR 200..201 -> IR 0..1
+		{Area: core.AreaInputRegs, Start: 200, Length: 2, Target: core.AreaInputRegs, Offset: 0},
+	})
+	l := newListener(resolve, Policy{View: view})
+
+	cases := []struct {
+		name string
+		pdu  PDU
+		want []byte
+	}{
+		{
+			name: "read remapped holding registers",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x0B, 0xB8, 0x00, 0x01}},
+			want: []byte{0x03, 0x02, 0x11, 0x11},
+		},
+		{
+			name: "unwindowed holding address",
+			pdu:  PDU{Function: 0x03, Data: []byte{0x00, 0x00, 0x00, 0x01}},
+			want: []byte{0x83, 0x02},
+		},
+		{
+			name: "input registers mirror holding registers",
+			pdu:  PDU{Function: 0x04, Data: []byte{0x00, 0x64, 0x00, 0x01}},
+			want: []byte{0x04, 0x02, 0x44, 0x44},
+		},
+		{
+			name: "read straddling two windows",
+			pdu:  PDU{Function: 0x04, Data: []byte{0x00, 0x67, 0x00, 0x02}},
+			want: []byte{0x84, 0x02},
+		},
+		{
+			name: "write through window",
+			pdu:  PDU{Function: 0x06, Data: []byte{0x0B, 0xB9, 0xAB, 0xCD}},
+			want: []byte{0x06, 0x0B, 0xB9, 0xAB, 0xCD},
+		},
+		{
+			name: "write past window end",
+			pdu:  PDU{Function: 0x10, Data: []byte{0x0B, 0xBB, 0x00, 0x02, 0x04, 0x00, 0x01, 0x00, 0x02}},
+			want: []byte{0x90, 0x02},
+		},
+		{
+			name: "coils without windows pass through",
+			pdu:  PDU{Function: 0x01, Data: []byte{0x00, 0x01, 0x00, 0x01}},
+			want: []byte{0x01, 0x01, 0x01},
+		},
+	}
+
+	for _, tc := range cases {
+		if got := serveRequest(1, tc.pdu, l); !bytes.Equal(got, tc.want) {
+			t.Fatalf("%s: expected % X, got % X", tc.name, tc.want, got)
+		}
+	}
+
+	if mem.HoldingRegs[1] != 0xABCD {
+		t.Fatalf("write did not land at HR[1]: %04X", mem.HoldingRegs[1])
+	}
+}
@@ -7,9 +7,15 @@ type Status struct {
 	Connected bool   `json:"connected"`
 	Broker    string `json:"broker"`
 	Topic     string `json:"topic"`
+
+	// Conditional commands not applied because their condition failed
+	Conflicts uint64 `json:"conflicts"`
 }
 
-var connected atomic.Bool
+var (
+	connected atomic.Bool
+	conflicts atomic.Uint64
+)
 
 func setConnected(v bool) {
 	connected.Store(v)
@@ -21,5 +27,6 @@ func GetStatus(cfg Config) Status {
 		Connected: connected.Load(),
 		Broker:    cfg.Broker,
 		Topic:     cfg.Topic,
+		Conflicts: conflicts.Load(),
 	}
 }
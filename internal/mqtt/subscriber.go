@@ -2,10 +2,12 @@ package mqtt
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 
+	"modbus-memory-appliance/internal/core"
 	"modbus-memory-appliance/internal/ingest"
 )
 
@@ -80,18 +82,69 @@ func NewSubscriber(cfg Config, ingestSvc *ingest.Service) (*Subscriber, error) {
 	return s, nil
 }
 
-// handleMessage converts MQTT payload → ingest.Command
+// disconnectQuiesceMS lets in-flight messages finish on Close.
+const disconnectQuiesceMS = 250
+
+// Close disconnects from the broker cleanly.
+func (s *Subscriber) Close() {
+	s.client.Disconnect(disconnectQuiesceMS)
+	setConnected(false)
+	log.Printf("mqtt disconnected")
+}
+
+// handleMessage converts MQTT payload → ingest.Command, or
+// ingest.Transaction when the payload carries "writes".
 // No retries, no buffering, no semantics.
 func (s *Subscriber) handleMessage(_ mqtt.Client, msg mqtt.Message) {
+	var probe struct {
+		Writes json.RawMessage `json:"writes"`
+	}
+	if err := json.Unmarshal(msg.Payload(), &probe); err != nil {
+		log.Printf("mqtt ingest: invalid json: %v", err)
+		return
+	}
+	if probe.Writes != nil {
+		s.handleTransaction(msg.Payload())
+		return
+	}
+
 	var cmd ingest.Command
 
 	if err := json.Unmarshal(msg.Payload(), &cmd); err != nil {
 		log.Printf("mqtt ingest: invalid json: %v", err)
 		return
 	}
+	cmd.Source = core.SourceMQTT
 
 	if err := s.ingest.Ingest(cmd); err != nil {
-		log.Printf("mqtt ingest rejected: %v", err)
+		logRejected("mqtt ingest", err)
+		return
+	}
+}
+
+// handleTransaction applies a multi-write payload atomically.
+func (s *Subscriber) handleTransaction(payload []byte) {
+	var tx ingest.Transaction
+
+	if err := json.Unmarshal(payload, &tx); err != nil {
+		log.Printf("mqtt ingest: invalid json: %v", err)
+		return
+	}
+	tx.Source = core.SourceMQTT
+
+	if err := s.ingest.IngestTransaction(tx); err != nil {
+		logRejected("mqtt ingest transaction", err)
+		return
+	}
+}
+
+// logRejected logs a rejected command. A failed condition is not an
+// error of the sender; it is logged and counted apart.
+func logRejected(what string, err error) {
+	if errors.Is(err, core.ErrConflict) {
+		conflicts.Add(1)
+		log.Printf("%s conflict: %v", what, err)
 		return
 	}
+	log.Printf("%s rejected: %v", what, err)
 }
@@ -0,0 +1,61 @@
+package persist
+
+import (
+	"os"
+	"path/filepath"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// Save writes s to path atomically: the snapshot goes to a temporary
+// file in the same directory, is synced, then renamed over path. A
+// crash leaves either the previous snapshot or the new one.
+func Save(path string, s core.Snapshot) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after the rename
+
+	if _, err := tmp.Write(Encode(s)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// Load reads the snapshot at path. A missing file returns an error
+// satisfying errors.Is(err, fs.ErrNotExist).
+func Load(path string) (core.Snapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return core.Snapshot{}, err
+	}
+	return Decode(b)
+}
+
+// syncDir makes the rename durable, where the platform supports
+// syncing a directory (not on Windows).
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+
+	_ = d.Sync()
+}
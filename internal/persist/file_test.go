@@ -0,0 +1,53 @@
+package persist
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plant.snap")
+	want := testSnapshot()
+
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite: the rename replaces the previous snapshot
+	want.HoldingRegs[1] = 42
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.HoldingRegs, want.HoldingRegs) {
+		t.Fatalf("loaded %v, want %v", got.HoldingRegs, want.HoldingRegs)
+	}
+
+	// No temporary files left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("expected only the snapshot, got %d entries", len(entries))
+	}
+}
+
+func TestLoad_Missing(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "none.snap"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestSave_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "no", "such", "plant.snap")
+	if err := Save(path, testSnapshot()); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+}
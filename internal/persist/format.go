@@ -0,0 +1,162 @@
+// Package persist stores memory snapshots on disk so a memory can be
+// retained across restarts.
+package persist
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// Snapshot file layout, big-endian like Modbus:
+//
+//	0   magic "MMAS"
+//	4   version uint16
+//	6   flags uint16 (bit 0: sealed; reserved 0 in version 1)
+//	8   memory generation uint64
+//	16  area generations 4 x uint64 (coils, discrete inputs, holding, input)
+//	48  area sizes 4 x uint32 (same order)
+//	64  coils, discrete inputs: packed bits, LSB first (as on Modbus)
+//	    holding, input registers: 2 bytes each
+//	end CRC-32C of everything before it
+const (
+	magic   = "MMAS"
+	Version = 2
+
+	flagSealed = 1 << 0
+
+	headerLen = 64
+	crcLen    = 4
+)
+
+var (
+	ErrCorrupt = errors.New("persist: corrupt snapshot")
+	ErrVersion = errors.New("persist: unsupported snapshot version")
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// areaOrder is the on-disk order of the four areas.
+var areaOrder = [4]core.Area{
+	core.AreaCoils,
+	core.AreaDiscreteInputs,
+	core.AreaHoldingRegs,
+	core.AreaInputRegs,
+}
+
+// Encode serializes s in the current Version.
+func Encode(s core.Snapshot) []byte {
+	size := headerLen +
+		bitsLen(len(s.Coils)) + bitsLen(len(s.DiscreteInputs)) +
+		2*len(s.HoldingRegs) + 2*len(s.InputRegs) +
+		crcLen
+
+	b := make([]byte, headerLen, size)
+	copy(b, magic)
+	binary.BigEndian.PutUint16(b[4:], Version)
+	if s.Sealed {
+		binary.BigEndian.PutUint16(b[6:], flagSealed)
+	}
+	binary.BigEndian.PutUint64(b[8:], s.Generation)
+
+	sizes := [4]int{len(s.Coils), len(s.DiscreteInputs), len(s.HoldingRegs), len(s.InputRegs)}
+	for i, a := range areaOrder {
+		binary.BigEndian.PutUint64(b[16+8*i:], s.AreaGenerations[a])
+		binary.BigEndian.PutUint32(b[48+4*i:], uint32(sizes[i]))
+	}
+
+	b = appendBits(b, s.Coils)
+	b = appendBits(b, s.DiscreteInputs)
+	b = appendRegs(b, s.HoldingRegs)
+	b = appendRegs(b, s.InputRegs)
+
+	return binary.BigEndian.AppendUint32(b, crc32.Checksum(b, crcTable))
+}
+
+// Decode parses a snapshot written by Encode. The checksum is verified
+// before anything else is trusted. A version 1 snapshot has no seal
+// state and decodes as not sealed.
+func Decode(b []byte) (core.Snapshot, error) {
+	var s core.Snapshot
+
+	if len(b) < headerLen+crcLen {
+		return s, ErrCorrupt
+	}
+	body, sum := b[:len(b)-crcLen], b[len(b)-crcLen:]
+	if crc32.Checksum(body, crcTable) != binary.BigEndian.Uint32(sum) {
+		return s, ErrCorrupt
+	}
+	if string(body[:4]) != magic {
+		return s, ErrCorrupt
+	}
+	switch binary.BigEndian.Uint16(body[4:]) {
+	case 1:
+		// no flags
+	case Version:
+		s.Sealed = binary.BigEndian.Uint16(body[6:])&flagSealed != 0
+	default:
+		return s, ErrVersion
+	}
+
+	s.Generation = binary.BigEndian.Uint64(body[8:])
+
+	var sizes [4]int
+	for i, a := range areaOrder {
+		s.AreaGenerations[a] = binary.BigEndian.Uint64(body[16+8*i:])
+		sizes[i] = int(binary.BigEndian.Uint32(body[48+4*i:]))
+	}
+
+	want := headerLen +
+		bitsLen(sizes[0]) + bitsLen(sizes[1]) +
+		2*sizes[2] + 2*sizes[3]
+	if len(body) != want {
+		return s, ErrCorrupt
+	}
+
+	p := body[headerLen:]
+	s.Coils, p = readBits(p, sizes[0])
+	s.DiscreteInputs, p = readBits(p, sizes[1])
+	s.HoldingRegs, p = readRegs(p, sizes[2])
+	s.InputRegs, _ = readRegs(p, sizes[3])
+
+	return s, nil
+}
+
+func bitsLen(n int) int {
+	return (n + 7) / 8
+}
+
+func appendBits(b []byte, bits []bool) []byte {
+	packed := make([]byte, bitsLen(len(bits)))
+	for i, v := range bits {
+		if v {
+			packed[i/8] |= 1 << (i % 8)
+		}
+	}
+	return append(b, packed...)
+}
+
+func appendRegs(b []byte, regs []uint16) []byte {
+	for _, v := range regs {
+		b = binary.BigEndian.AppendUint16(b, v)
+	}
+	return b
+}
+
+func readBits(p []byte, n int) ([]bool, []byte) {
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = p[i/8]&(1<<(i%8)) != 0
+	}
+	return bits, p[bitsLen(n):]
+}
+
+func readRegs(p []byte, n int) ([]uint16, []byte) {
+	regs := make([]uint16, n)
+	for i := range regs {
+		regs[i] = binary.BigEndian.Uint16(p[2*i:])
+	}
+	return regs, p[2*n:]
+}
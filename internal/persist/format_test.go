@@ -0,0 +1,112 @@
+package persist
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"slices"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func testSnapshot() core.Snapshot {
+	mem := core.NewMemory(11, 3, 2, 4)
+	_ = mem.WriteCoils(0, []bool{true, false, true})
+	_ = mem.WriteCoils(10, []bool{true})
+	_ = mem.WriteDiscreteInputs(2, []bool{true})
+	_ = mem.WriteHoldingRegs(0, []uint16{0xBEEF, 1})
+	_ = mem.WriteInputRegs(3, []uint16{0xFFFF})
+	return mem.Snapshot()
+}
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	want := testSnapshot()
+
+	got, err := Decode(Encode(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(got.Coils, want.Coils) ||
+		!slices.Equal(got.DiscreteInputs, want.DiscreteInputs) ||
+		!slices.Equal(got.HoldingRegs, want.HoldingRegs) ||
+		!slices.Equal(got.InputRegs, want.InputRegs) ||
+		got.Generation != want.Generation ||
+		got.AreaGenerations != want.AreaGenerations {
+		t.Fatalf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestDecode_DetectsCorruption(t *testing.T) {
+	good := Encode(testSnapshot())
+
+	cases := map[string][]byte{
+		"empty":     nil,
+		"truncated": good[:len(good)-1],
+		"bit flip":  flip(good, headerLen+1),
+		"bad crc":   flip(good, len(good)-1),
+	}
+	for name, b := range cases {
+		if _, err := Decode(b); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("%s: expected ErrCorrupt, got %v", name, err)
+		}
+	}
+}
+
+func TestEncodeDecode_SealState(t *testing.T) {
+	preRun := core.NewMemory(1, 1, 1, 1)
+	preRun.SetStateSealing(true, 0)
+
+	for _, mem := range []*core.Memory{preRun, core.NewMemory(1, 1, 1, 1)} {
+		want := mem.Snapshot()
+		got, err := Decode(Encode(want))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Sealed != want.Sealed {
+			t.Fatalf("decoded sealed=%v, want %v", got.Sealed, want.Sealed)
+		}
+	}
+}
+
+func TestDecode_Version1IsNotSealed(t *testing.T) {
+	snap := testSnapshot()
+	if !snap.Sealed {
+		t.Fatal("expected a RUN memory to snapshot as sealed")
+	}
+
+	// Version 1 header: no flags
+	b := Encode(snap)
+	binary.BigEndian.PutUint16(b[4:], 1)
+	binary.BigEndian.PutUint16(b[6:], 0)
+	body := b[:len(b)-crcLen]
+	binary.BigEndian.PutUint32(b[len(body):], crc32.Checksum(body, crcTable))
+
+	got, err := Decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Sealed || !slices.Equal(got.HoldingRegs, snap.HoldingRegs) {
+		t.Fatalf("decoded %+v", got)
+	}
+}
+
+func TestDecode_RejectsUnknownVersion(t *testing.T) {
+	b := Encode(testSnapshot())
+	b[5] = Version + 1
+
+	// Intact file of a newer version: re-seal the checksum
+	body := b[:len(b)-crcLen]
+	binary.BigEndian.PutUint32(b[len(body):], crc32.Checksum(body, crcTable))
+
+	if _, err := Decode(b); !errors.Is(err, ErrVersion) {
+		t.Fatalf("expected ErrVersion, got %v", err)
+	}
+}
+
+func flip(b []byte, i int) []byte {
+	out := slices.Clone(b)
+	out[i] ^= 0x01
+	return out
+}
@@ -0,0 +1,29 @@
+// internal/poller/errors.go
+// PURPOSE: Poller error definitions.
+// ALLOWED: sentinel errors and error types
+// FORBIDDEN: logic
+
+package poller
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidResponse is a response that does not answer the request.
+	ErrInvalidResponse = errors.New("poller: invalid response")
+
+	// errWrite marks memory write failures.
+	errWrite = errors.New("poller: memory write failed")
+)
+
+// ExceptionError is a Modbus exception returned by the device.
+type ExceptionError struct {
+	Function uint8
+	Code     uint8
+}
+
+func (e *ExceptionError) Error() string {
+	return fmt.Sprintf("poller: device exception 0x%02X on function 0x%02X", e.Code, e.Function)
+}
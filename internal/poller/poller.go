@@ -0,0 +1,145 @@
+// internal/poller/poller.go
+// PURPOSE: Poll Modbus TCP devices into memory (ingest adapter).
+// ALLOWED: poll scheduling, atomic core.Memory writes
+// FORBIDDEN: serving Modbus, config parsing
+
+package poller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/modbus"
+)
+
+// Read copies Count values from a device area into memory.
+type Read struct {
+	Area    core.Area // device area
+	Address int       // device address
+	Count   int
+
+	Target        core.Area // discrete inputs or input registers
+	TargetAddress int       // external memory address
+}
+
+// Config describes one poller. It is validated by config.
+type Config struct {
+	Name     string
+	Address  string // host:port
+	UnitID   uint8
+	Memory   string // memory ID (diagnostics only)
+	Interval time.Duration
+	Timeout  time.Duration
+	Reads    []Read
+}
+
+// Poller reads a device on a fixed cadence. Each read lands in memory
+// as one atomic write; a failed read leaves its target untouched.
+type Poller struct {
+	cfg    Config
+	mem    *core.Memory
+	client *modbus.Upstream
+	stats  stats
+}
+
+// New creates a poller writing into mem. It keeps one connection to
+// the device, opened on demand.
+func New(cfg Config, mem *core.Memory) *Poller {
+	return &Poller{
+		cfg:    cfg,
+		mem:    mem,
+		client: modbus.NewUpstream(cfg.Address, 1, cfg.Timeout),
+	}
+}
+
+// Run polls until ctx is cancelled. The first poll starts immediately;
+// a slow cycle delays the next one rather than overlapping it.
+func (p *Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.poll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll runs one cycle over every configured read.
+func (p *Poller) poll() {
+	for _, r := range p.cfg.Reads {
+		p.stats.requests.Add(1)
+
+		if err := p.read(r); err != nil {
+			var exc *ExceptionError
+			switch {
+			case errors.As(err, &exc):
+				p.stats.fail(&p.stats.exceptions, err)
+			case errors.Is(err, errWrite):
+				p.stats.fail(&p.stats.writeErrors, err)
+			default:
+				p.stats.fail(&p.stats.commErrors, err)
+			}
+			continue
+		}
+
+		p.stats.succeed()
+	}
+}
+
+func (p *Poller) read(r Read) error {
+	resp, err := p.client.Do(p.cfg.UnitID, readRequest(r))
+	if err != nil {
+		return err
+	}
+
+	payload, err := checkResponse(r, resp)
+	if err != nil {
+		return err
+	}
+
+	addr := p.mem.ToInternal(r.Target, r.TargetAddress)
+
+	// One atomic write per read (State Sealing applies as for any DI write)
+	if r.Target == core.AreaDiscreteInputs {
+		err = p.mem.From(core.SourcePoller).WriteDiscreteInputs(addr, decodeBits(payload, r.Count))
+	} else {
+		err = p.mem.From(core.SourcePoller).WriteInputRegs(addr, decodeRegs(payload, r.Count))
+	}
+	if err != nil {
+		return errors.Join(errWrite, err)
+	}
+	return nil
+}
+
+// Status returns a snapshot of the poller's counters.
+func (p *Poller) Status() Status {
+	s := &p.stats
+
+	out := Status{
+		Name:        p.cfg.Name,
+		Address:     p.cfg.Address,
+		UnitID:      p.cfg.UnitID,
+		Memory:      p.cfg.Memory,
+		Requests:    s.requests.Load(),
+		CommErrors:  s.commErrors.Load(),
+		Exceptions:  s.exceptions.Load(),
+		WriteErrors: s.writeErrors.Load(),
+	}
+
+	s.mu.Lock()
+	out.LastError = s.lastError
+	if !s.lastSuccess.IsZero() {
+		t := s.lastSuccess
+		out.LastSuccess = &t
+	}
+	s.mu.Unlock()
+
+	return out
+}
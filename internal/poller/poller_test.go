@@ -0,0 +1,162 @@
+package poller
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"slices"
+	"testing"
+	"time"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// startStandIn serves a minimal Modbus TCP device: 16 holding registers
+// holding their own address + 100, coils alternating on/off. Any other
+// request gets Illegal Data Address.
+func startStandIn(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveStandIn(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func serveStandIn(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		hdr := make([]byte, 7)
+		if _, err := io.ReadFull(conn, hdr); err != nil {
+			return
+		}
+		pdu := make([]byte, binary.BigEndian.Uint16(hdr[4:6])-1)
+		if _, err := io.ReadFull(conn, pdu); err != nil {
+			return
+		}
+
+		fc := pdu[0]
+		addr := int(binary.BigEndian.Uint16(pdu[1:3]))
+		count := int(binary.BigEndian.Uint16(pdu[3:5]))
+
+		var resp []byte
+		switch {
+		case fc == 0x03 && addr+count <= 16:
+			resp = []byte{fc, byte(count * 2)}
+			for i := 0; i < count; i++ {
+				resp = binary.BigEndian.AppendUint16(resp, uint16(addr+i+100))
+			}
+		case fc == 0x01 && addr+count <= 16:
+			bits := make([]byte, (count+7)/8)
+			for i := 0; i < count; i++ {
+				if (addr+i)%2 == 0 {
+					bits[i/8] |= 1 << (i % 8)
+				}
+			}
+			resp = append([]byte{fc, byte(len(bits))}, bits...)
+		default:
+			resp = []byte{fc | 0x80, 0x02}
+		}
+
+		binary.BigEndian.PutUint16(hdr[4:6], uint16(len(resp)+1))
+		if _, err := conn.Write(append(hdr, resp...)); err != nil {
+			return
+		}
+	}
+}
+
+func TestPoller_CopiesRanges(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	mem.SetAddressBase(core.AreaInputRegs, 1000)
+
+	p := New(Config{
+		Name:     "meter",
+		Address:  startStandIn(t),
+		UnitID:   1,
+		Interval: time.Hour,
+		Timeout:  time.Second,
+		Reads: []Read{
+			{Area: core.AreaHoldingRegs, Address: 2, Count: 3, Target: core.AreaInputRegs, TargetAddress: 1001},
+			{Area: core.AreaCoils, Address: 0, Count: 4, Target: core.AreaDiscreteInputs, TargetAddress: 4},
+		},
+	}, mem)
+
+	p.poll()
+
+	regs, _ := mem.ReadInputRegs(0, 4)
+	if want := []uint16{0, 102, 103, 104}; !slices.Equal(regs, want) {
+		t.Fatalf("expected input registers %v, got %v", want, regs)
+	}
+	bits, _ := mem.ReadDiscreteInputs(4, 4)
+	if bits[0] != true || bits[1] != false || bits[2] != true || bits[3] != false {
+		t.Fatalf("unexpected discrete inputs %v", bits)
+	}
+
+	st := p.Status()
+	if st.Requests != 2 || st.CommErrors != 0 || st.Exceptions != 0 || st.LastSuccess == nil {
+		t.Fatalf("unexpected status %+v", st)
+	}
+}
+
+func TestPoller_ErrorCounters(t *testing.T) {
+	mem := core.NewMemory(8, 8, 8, 8)
+	mem.InputRegs[0] = 0xBEEF
+
+	p := New(Config{
+		Address: startStandIn(t),
+		UnitID:  1,
+		Timeout: time.Second,
+		Reads: []Read{
+			// Past the device's registers: exception, memory untouched
+			{Area: core.AreaHoldingRegs, Address: 15, Count: 2, Target: core.AreaInputRegs},
+			// Past the memory: write error
+			{Area: core.AreaHoldingRegs, Address: 0, Count: 4, Target: core.AreaInputRegs, TargetAddress: 6},
+		},
+	}, mem)
+
+	p.poll()
+
+	if mem.InputRegs[0] != 0xBEEF {
+		t.Fatalf("failed read must not write, got %04X", mem.InputRegs[0])
+	}
+
+	st := p.Status()
+	if st.Exceptions != 1 || st.WriteErrors != 1 || st.LastError == "" {
+		t.Fatalf("unexpected status %+v", st)
+	}
+
+	// Unreachable device
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().String()
+	ln.Close()
+
+	down := New(Config{
+		Address:  addr,
+		Interval: 10 * time.Millisecond,
+		Timeout:  100 * time.Millisecond,
+		Reads:    []Read{{Area: core.AreaHoldingRegs, Count: 1, Target: core.AreaInputRegs}},
+	}, mem)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	down.Run(ctx)
+
+	if st := down.Status(); st.CommErrors == 0 || st.CommErrors != st.Requests {
+		t.Fatalf("expected every request to fail, got %+v", st)
+	}
+}
@@ -0,0 +1,73 @@
+// internal/poller/read.go
+// PURPOSE: Build read requests and decode their responses.
+// ALLOWED: PDU encoding/decoding
+// FORBIDDEN: network access, memory access
+
+package poller
+
+import (
+	"encoding/binary"
+
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/modbus"
+)
+
+// readFunction returns the read function code of a device area.
+func readFunction(area core.Area) uint8 {
+	switch area {
+	case core.AreaCoils:
+		return 0x01
+	case core.AreaDiscreteInputs:
+		return 0x02
+	case core.AreaHoldingRegs:
+		return 0x03
+	default:
+		return 0x04
+	}
+}
+
+func isBitArea(area core.Area) bool {
+	return area == core.AreaCoils || area == core.AreaDiscreteInputs
+}
+
+func readRequest(r Read) modbus.PDU {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint16(data[0:2], uint16(r.Address))
+	binary.BigEndian.PutUint16(data[2:4], uint16(r.Count))
+	return modbus.PDU{Function: readFunction(r.Area), Data: data}
+}
+
+// checkResponse validates a read response and returns its payload.
+func checkResponse(r Read, resp []byte) ([]byte, error) {
+	fc := readFunction(r.Area)
+
+	if len(resp) == 2 && resp[0] == fc|0x80 {
+		return nil, &ExceptionError{Function: fc, Code: resp[1]}
+	}
+
+	want := r.Count * 2
+	if isBitArea(r.Area) {
+		want = (r.Count + 7) / 8
+	}
+
+	if len(resp) != 2+want || resp[0] != fc || int(resp[1]) != want {
+		return nil, ErrInvalidResponse
+	}
+	return resp[2:], nil
+}
+
+func decodeBits(payload []byte, count int) []bool {
+	out := make([]bool, count)
+	for i := range out {
+		out[i] = payload[i/8]&(1<<(i%8)) != 0
+	}
+	return out
+}
+
+func decodeRegs(payload []byte, count int) []uint16 {
+	out := make([]uint16, count)
+	for i := range out {
+		out[i] = binary.BigEndian.Uint16(payload[i*2:])
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+// internal/poller/status.go
+// PURPOSE: Per-poller counters for diagnostics.
+// ALLOWED: atomic counters, snapshots
+// FORBIDDEN: network access, memory access
+
+package poller
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Status is a snapshot of one poller's counters.
+type Status struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	UnitID   uint8  `json:"unit_id"`
+	Memory   string `json:"memory"`
+	Requests uint64 `json:"requests"`
+
+	// Errors by kind
+	CommErrors  uint64 `json:"comm_errors"` // unreachable, timeout, bad frame
+	Exceptions  uint64 `json:"exceptions"`  // Modbus exception from the device
+	WriteErrors uint64 `json:"write_errors"`
+
+	LastError   string     `json:"last_error,omitempty"`
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+}
+
+type stats struct {
+	requests    atomic.Uint64
+	commErrors  atomic.Uint64
+	exceptions  atomic.Uint64
+	writeErrors atomic.Uint64
+
+	mu          sync.Mutex
+	lastError   string
+	lastSuccess time.Time
+}
+
+func (s *stats) fail(counter *atomic.Uint64, err error) {
+	counter.Add(1)
+
+	s.mu.Lock()
+	s.lastError = err.Error()
+	s.mu.Unlock()
+}
+
+func (s *stats) succeed() {
+	s.mu.Lock()
+	s.lastSuccess = time.Now()
+	s.mu.Unlock()
+}
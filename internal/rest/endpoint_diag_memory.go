@@ -1,10 +1,15 @@
 // File: endpoint_diag_memory.go
 // Endpoint: GET /api/v1/diagnostics/memory
-// Purpose: Report configured memory layouts (config truth)
+// Purpose: Report configured memory layouts (config truth) and change
+// notification counters (runtime truth)
 
 package rest
 
-import "net/http"
+import (
+	"net/http"
+
+	"modbus-memory-appliance/internal/config"
+)
 
 func (h *Handlers) HandleDiagnosticsMemory(w http.ResponseWriter, r *http.Request) {
 	if !h.EnableDiagnostics {
@@ -15,28 +20,42 @@ func (h *Handlers) HandleDiagnosticsMemory(w http.ResponseWriter, r *http.Reques
 	out := make(map[string]any)
 
 	for name, mem := range h.MemoryConfig.Memories {
-		out[name] = map[string]any{
-			"default": mem.Default,
-			"coils": map[string]any{
-				"start": mem.Coils.Start,
-				"size":  mem.Coils.Size,
-			},
-			"discrete_inputs": map[string]any{
-				"start": mem.DiscreteInputs.Start,
-				"size":  mem.DiscreteInputs.Size,
-			},
-			"holding_registers": map[string]any{
-				"start": mem.HoldingRegisters.Start,
-				"size":  mem.HoldingRegisters.Size,
-			},
-			"input_registers": map[string]any{
-				"start": mem.InputRegisters.Start,
-				"size":  mem.InputRegisters.Size,
-			},
+		entry := map[string]any{
+			"default":           mem.Default,
+			"coils":             areaLayout(mem.Coils),
+			"discrete_inputs":   areaLayout(mem.DiscreteInputs),
+			"holding_registers": areaLayout(mem.HoldingRegisters),
+			"input_registers":   areaLayout(mem.InputRegisters),
+		}
+
+		if m, ok := h.Memories[name]; ok {
+			entry["changes"] = map[string]any{
+				"subscribers": m.Subscribers(),
+				"dropped":     m.DroppedChanges(),
+			}
 		}
+
+		out[name] = entry
 	}
 
 	writeJSON(w, http.StatusOK, map[string]any{
 		"memories": out,
 	})
 }
+
+// areaLayout reports an area with its external (wire) address window
+// and the zero-based internal index range it maps to.
+func areaLayout(a config.AreaConfig) map[string]any {
+	return map[string]any{
+		"start": a.Start,
+		"size":  a.Size,
+		"external": map[string]any{
+			"first": a.Start,
+			"last":  a.Start + a.Size - 1,
+		},
+		"internal": map[string]any{
+			"first": 0,
+			"last":  a.Size - 1,
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package rest
+
+import "net/http"
+
+// HandleDiagnosticsModbus exposes Modbus listener counters via REST.
+// It is READ-ONLY diagnostics.
+func (h *Handlers) HandleDiagnosticsModbus(w http.ResponseWriter, r *http.Request) {
+	if !h.EnableDiagnostics {
+		writeJSON(w, http.StatusForbidden, reject("diagnostics disabled"))
+		return
+	}
+
+	// No Modbus listeners configured
+	if h.ModbusStatus == nil {
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.ModbusStatus())
+}
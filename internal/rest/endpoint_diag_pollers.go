@@ -0,0 +1,20 @@
+package rest
+
+import "net/http"
+
+// HandleDiagnosticsPollers exposes poller counters via REST.
+// It is READ-ONLY diagnostics.
+func (h *Handlers) HandleDiagnosticsPollers(w http.ResponseWriter, r *http.Request) {
+	if !h.EnableDiagnostics {
+		writeJSON(w, http.StatusForbidden, reject("diagnostics disabled"))
+		return
+	}
+
+	// No pollers configured
+	if h.PollerStatus == nil {
+		writeJSON(w, http.StatusOK, []any{})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, h.PollerStatus())
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"strings"
 
+	"modbus-memory-appliance/internal/core"
 	"modbus-memory-appliance/internal/ingest"
 )
 
@@ -28,16 +29,8 @@ func (h *Handlers) HandleIngest(w http.ResponseWriter, r *http.Request) {
 
 	req.Area = strings.TrimSpace(req.Area)
 
-	// REST ingest rule: only discrete_inputs and input_registers
-	if req.Area != "discrete_inputs" && req.Area != "input_registers" {
-		h.Stats.IncRejected()
-		writeJSON(
-			w,
-			http.StatusForbidden,
-			rejectWith("area is not writable via ingest", "area", req.Area),
-		)
-		return
-	}
+	// Which areas are writable is decided by ingest.Service (State
+	// Sealing), the same for every transport.
 
 	// Payload validation
 	if req.Area == "discrete_inputs" {
@@ -70,6 +63,7 @@ func (h *Handlers) HandleIngest(w http.ResponseWriter, r *http.Request) {
 		Address: req.Address,
 		Bools:   req.Bools,
 		Values:  req.Values,
+		Source:  core.SourceREST,
 	}
 
 	h.Stats.IncIngest()
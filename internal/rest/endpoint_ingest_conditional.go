@@ -0,0 +1,123 @@
+// File: endpoint_ingest_conditional.go
+// Endpoint: POST /api/v1/ingest/conditional
+// Purpose: Compare-and-swap write for cooperating writers
+
+package rest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/ingest"
+)
+
+// HandleIngestConditional writes only while a condition holds: the
+// current values equal expect_bools / expect_values, and / or the area
+// is unchanged since if_generation (or the If-Match ETag of a read). A
+// version of an earlier boot always conflicts.
+// A failed condition answers 409 with the current values.
+//
+// Areas follow the same State Sealing rule as /ingest: coils and
+// holding registers only in PRE-RUN.
+func (h *Handlers) HandleIngestConditional(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, reject("method not allowed"))
+		return
+	}
+
+	if !h.EnableIngest {
+		writeJSON(w, http.StatusForbidden, reject("ingest disabled"))
+		return
+	}
+
+	var req conditionalRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Stats.IncRejected()
+		writeJSON(w, http.StatusBadRequest, reject("invalid json"))
+		return
+	}
+
+	// If-Match carries the ETag of /memory/read: "<epoch>-<area_generation>"
+	if tag := r.Header.Get("If-Match"); tag != "" {
+		req.IfGeneration = strings.Trim(tag, `"`)
+	}
+
+	if req.IfGeneration == "" && len(req.ExpectBools) == 0 && len(req.ExpectValues) == 0 {
+		h.Stats.IncRejected()
+		writeJSON(
+			w,
+			http.StatusBadRequest,
+			reject("expect_bools, expect_values or if_generation required"),
+		)
+		return
+	}
+
+	cmd := ingest.Command{
+		Memory:       req.Memory,
+		Area:         ingest.Area(strings.TrimSpace(req.Area)),
+		Address:      req.Address,
+		Bools:        req.Bools,
+		Values:       req.Values,
+		ExpectBools:  req.ExpectBools,
+		ExpectValues: req.ExpectValues,
+		IfGeneration: req.IfGeneration,
+		Source:       core.SourceREST,
+	}
+
+	h.Stats.IncIngest()
+	h.Stats.IncIngestBatch()
+
+	if err := h.Ingest.Ingest(cmd); err != nil {
+		h.Stats.IncRejected()
+		h.Stats.IncIngestRejected()
+
+		if errors.Is(err, core.ErrConflict) {
+			writeJSON(w, http.StatusConflict, h.conflict(cmd, err))
+			return
+		}
+		writeIngestError(w, err)
+		return
+	}
+
+	written := len(req.Bools) + len(req.Values)
+	h.Stats.AddWrittenRegs(uint32(written))
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":  "accepted",
+		"memory":  req.Memory,
+		"written": written,
+	})
+}
+
+// conflict builds the 409 body. The current values and generation let
+// the client retry without another read.
+func (h *Handlers) conflict(cmd ingest.Command, err error) map[string]any {
+	resp := map[string]any{
+		"status": "conflict",
+		"error":  err.Error(),
+	}
+
+	// Same memory the command was written to, fallback included
+	mem, err := h.Ingest.Resolve(cmd.Memory)
+	a, known := core.ParseArea(string(cmd.Area))
+	if err != nil || !known {
+		return resp
+	}
+
+	v, err := mem.ReadVersioned(a, mem.ToInternal(a, int(cmd.Address)), len(cmd.Bools)+len(cmd.Values))
+	if err != nil {
+		return resp
+	}
+
+	if v.Bits != nil {
+		resp["values"] = v.Bits
+	} else {
+		resp["values"] = v.Registers
+	}
+	resp["area_generation"] = v.AreaGeneration
+	resp["version"] = v.Version().String()
+	return resp
+}
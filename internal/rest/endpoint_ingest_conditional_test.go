@@ -0,0 +1,52 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestHandleIngestConditional_DeniedInRun(t *testing.T) {
+	// State Sealing disabled: the memory is in RUN from the start
+	mem := core.NewMemory(10, 10, 10, 10)
+	h := newTestHandlers(mem)
+
+	rec := post(t, h.HandleIngestConditional,
+		`{"memory":"test","area":"holding_registers","address":0,"values":[1500],"expect_values":[0]}`)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body)
+	}
+
+	if hr, _ := mem.ReadHoldingRegs(0, 1); hr[0] != 0 {
+		t.Fatalf("holding register written in RUN: %d", hr[0])
+	}
+}
+
+func TestHandleIngestConditional_ConflictUsesFallbackMemory(t *testing.T) {
+	mem := core.NewMemory(10, 10, 10, 10)
+	h := newTestHandlers(mem)
+	h.Ingest.SetFallbackMemory("test")
+
+	// No memory name: resolved by default_fallback
+	rec := post(t, h.HandleIngestConditional,
+		`{"area":"input_registers","address":0,"values":[5],"expect_values":[1]}`)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Values  []uint16 `json:"values"`
+		Version string   `json:"version"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Values) != 1 || resp.Values[0] != 0 {
+		t.Fatalf("expected current values [0], got %v", resp.Values)
+	}
+	if want := mem.Epoch() + "-0"; resp.Version != want {
+		t.Fatalf("expected version %s, got %q", want, resp.Version)
+	}
+}
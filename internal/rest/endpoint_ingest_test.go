@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+// Every ingest endpoint applies the State Sealing rule of ingest.Service:
+// holding registers only in PRE-RUN.
+func TestIngestEndpoints_HoldingRegistersFollowState(t *testing.T) {
+	endpoints := []struct {
+		name    string
+		handler func(h *Handlers) http.HandlerFunc
+		body    string
+	}{
+		{"ingest", func(h *Handlers) http.HandlerFunc { return h.HandleIngest },
+			`{"memory":"test","area":"holding_registers","address":0,"values":[7]}`},
+		{"transaction", func(h *Handlers) http.HandlerFunc { return h.HandleIngestTransaction },
+			`{"memory":"test","writes":[{"area":"holding_registers","address":0,"values":[7]}]}`},
+		{"conditional", func(h *Handlers) http.HandlerFunc { return h.HandleIngestConditional },
+			`{"memory":"test","area":"holding_registers","address":0,"values":[7],"expect_values":[0]}`},
+	}
+
+	for _, ep := range endpoints {
+		t.Run(ep.name+"/run", func(t *testing.T) {
+			mem := core.NewMemory(10, 10, 10, 10) // no State Sealing: RUN
+			rec := post(t, ep.handler(newTestHandlers(mem)), ep.body)
+			if rec.Code != http.StatusForbidden {
+				t.Fatalf("expected 403, got %d: %s", rec.Code, rec.Body)
+			}
+		})
+
+		t.Run(ep.name+"/pre-run", func(t *testing.T) {
+			mem := core.NewMemory(10, 10, 10, 10)
+			mem.SetStateSealing(true, 9)
+			rec := post(t, ep.handler(newTestHandlers(mem)), ep.body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+			}
+			if hr, _ := mem.ReadHoldingRegs(0, 1); hr[0] != 7 {
+				t.Fatalf("expected 7, got %d", hr[0])
+			}
+		})
+	}
+}
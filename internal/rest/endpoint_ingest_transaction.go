@@ -0,0 +1,82 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/ingest"
+)
+
+// HandleIngestTransaction applies several ingest writes atomically:
+// Modbus readers observe all of them or none. Like /ingest/conditional
+// the transaction may carry conditions; a failed one answers 409.
+func (h *Handlers) HandleIngestTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, reject("method not allowed"))
+		return
+	}
+
+	if !h.EnableIngest {
+		writeJSON(w, http.StatusForbidden, reject("ingest disabled"))
+		return
+	}
+
+	var req ingestTransaction
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.Stats.IncRejected()
+		writeJSON(w, http.StatusBadRequest, reject("invalid json"))
+		return
+	}
+
+	if len(req.Writes) == 0 {
+		h.Stats.IncRejected()
+		writeJSON(w, http.StatusBadRequest, reject("writes required"))
+		return
+	}
+
+	// If-Match carries the ETag of /memory/read, as for /ingest/conditional
+	if tag := r.Header.Get("If-Match"); tag != "" {
+		req.IfGeneration = strings.Trim(tag, `"`)
+	}
+
+	tx := ingest.Transaction{
+		Memory:       req.Memory,
+		Writes:       make([]ingest.Write, len(req.Writes)),
+		IfGeneration: req.IfGeneration,
+		Source:       core.SourceREST,
+	}
+
+	written := 0
+	for i, wr := range req.Writes {
+		tx.Writes[i] = ingest.Write{
+			Area:         ingest.Area(strings.TrimSpace(wr.Area)),
+			Address:      wr.Address,
+			Bools:        wr.Bools,
+			Values:       wr.Values,
+			ExpectBools:  wr.ExpectBools,
+			ExpectValues: wr.ExpectValues,
+		}
+		written += len(wr.Bools) + len(wr.Values)
+	}
+
+	h.Stats.IncIngest()
+	h.Stats.IncIngestBatch()
+
+	if err := h.Ingest.IngestTransaction(tx); err != nil {
+		h.Stats.IncRejected()
+		h.Stats.IncIngestRejected()
+		writeIngestError(w, err)
+		return
+	}
+
+	h.Stats.AddWrittenRegs(uint32(written))
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status":  "accepted",
+		"memory":  req.Memory,
+		"writes":  len(req.Writes),
+		"written": written,
+	})
+}
@@ -0,0 +1,59 @@
+package rest
+
+import (
+	"net/http"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func TestHandleIngestTransaction_ConditionFails(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"expect_values", `{"memory":"test","writes":[
+			{"area":"discrete_inputs","address":0,"bools":[1]},
+			{"area":"input_registers","address":0,"values":[5],"expect_values":[1]}]}`},
+		{"expect_bools", `{"memory":"test","writes":[
+			{"area":"discrete_inputs","address":0,"bools":[1],"expect_bools":[1]},
+			{"area":"input_registers","address":0,"values":[5]}]}`},
+		{"if_generation", `{"memory":"test","if_generation":"0000000000000000-0","writes":[
+			{"area":"discrete_inputs","address":0,"bools":[1]},
+			{"area":"input_registers","address":0,"values":[5]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mem := core.NewMemory(10, 10, 10, 10)
+			h := newTestHandlers(mem)
+
+			rec := post(t, h.HandleIngestTransaction, tt.body)
+			if rec.Code != http.StatusConflict {
+				t.Fatalf("expected 409, got %d: %s", rec.Code, rec.Body)
+			}
+
+			di, _ := mem.ReadDiscreteInputs(0, 1)
+			ir, _ := mem.ReadInputRegs(0, 1)
+			if di[0] || ir[0] != 0 || mem.Generation() != 0 {
+				t.Fatal("conditional transaction applied despite failed condition")
+			}
+		})
+	}
+}
+
+func TestHandleIngestTransaction_ConditionHolds(t *testing.T) {
+	mem := core.NewMemory(10, 10, 10, 10)
+	h := newTestHandlers(mem)
+
+	rec := post(t, h.HandleIngestTransaction, `{"memory":"test","if_generation":"`+mem.Epoch()+`-0","writes":[
+		{"area":"discrete_inputs","address":0,"bools":[1],"expect_bools":[0]},
+		{"area":"input_registers","address":0,"values":[5],"expect_values":[0]}]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body)
+	}
+
+	if ir, _ := mem.ReadInputRegs(0, 1); ir[0] != 5 {
+		t.Fatalf("expected 5, got %d", ir[0])
+	}
+}
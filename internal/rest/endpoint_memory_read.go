@@ -1,12 +1,15 @@
 // File: endpoint_memory_read.go
 // Endpoint: GET /api/v1/memory/read
 // Purpose: Read memory values (explicit memory selection)
+// Addresses are external (AreaConfig.Start based), as on Modbus.
 
 package rest
 
 import (
 	"net/http"
 	"strconv"
+
+	"modbus-memory-appliance/internal/core"
 )
 
 func (h *Handlers) HandleMemoryRead(w http.ResponseWriter, r *http.Request) {
@@ -48,40 +51,38 @@ func (h *Handlers) HandleMemoryRead(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	switch area {
-	case "coils":
-		vals, err := mem.ReadCoils(addr, count)
-		if err != nil {
-			writeIngestError(w, err)
-			return
-		}
-		writeJSON(w, http.StatusOK, map[string]any{"values": vals})
-
-	case "discrete_inputs":
-		vals, err := mem.ReadDiscreteInputs(addr, count)
-		if err != nil {
-			writeIngestError(w, err)
-			return
-		}
-		writeJSON(w, http.StatusOK, map[string]any{"values": vals})
+	a, ok := core.ParseArea(area)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, reject("unknown area"))
+		return
+	}
 
-	case "holding_registers":
-		vals, err := mem.ReadHoldingRegs(addr, count)
-		if err != nil {
-			writeIngestError(w, err)
-			return
-		}
-		writeJSON(w, http.StatusOK, map[string]any{"values": vals})
+	// Values and generations come from one consistent read
+	v, err := mem.ReadVersioned(a, mem.ToInternal(a, addr), count)
+	if err != nil {
+		writeIngestError(w, err)
+		return
+	}
 
-	case "input_registers":
-		vals, err := mem.ReadInputRegs(addr, count)
-		if err != nil {
-			writeIngestError(w, err)
-			return
-		}
-		writeJSON(w, http.StatusOK, map[string]any{"values": vals})
+	// The ETag is the version of the values: unchanged area → 304 (the
+	// memory generation when they include the generation registers).
+	// Its epoch makes tags of an earlier boot never match.
+	etag := `"` + v.Version().String() + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 
-	default:
-		writeJSON(w, http.StatusBadRequest, reject("unknown area"))
+	var values any = v.Registers
+	if v.Bits != nil {
+		values = v.Bits
 	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"values":          values,
+		"generation":      v.Generation,
+		"area_generation": v.AreaGeneration,
+		"version":         v.Version().String(),
+	})
 }
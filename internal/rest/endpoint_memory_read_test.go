@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+)
+
+func getMemory(h *Handlers, query, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/memory/read?"+query, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	h.HandleMemoryRead(rec, req)
+	return rec
+}
+
+func TestHandleMemoryRead_NotModified(t *testing.T) {
+	mem := core.NewMemory(10, 10, 10, 10)
+	h := newTestHandlers(mem)
+	h.EnableRead = true
+
+	const query = "memory=test&area=input_registers&address=0&count=2"
+	etag := getMemory(h, query, "").Header().Get("ETag")
+
+	// A write to another area leaves the input registers unchanged
+	_ = mem.WriteHoldingRegs(0, []uint16{1})
+
+	if rec := getMemory(h, query, etag); rec.Code != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", rec.Code)
+	}
+}
+
+func TestHandleMemoryRead_GenerationRegisterChangesETag(t *testing.T) {
+	mem := core.NewMemory(10, 10, 10, 10)
+	if err := mem.SetGenerationRegister(4); err != nil {
+		t.Fatal(err)
+	}
+	h := newTestHandlers(mem)
+	h.EnableRead = true
+
+	// Covers the generation registers at 4..7
+	const query = "memory=test&area=input_registers&address=0&count=8"
+	etag := getMemory(h, query, "").Header().Get("ETag")
+
+	_ = mem.WriteHoldingRegs(0, []uint16{1})
+
+	rec := getMemory(h, query, etag)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 after the generation changed, got %d", rec.Code)
+	}
+	if rec.Header().Get("ETag") == etag {
+		t.Fatal("ETag unchanged although the generation register changed")
+	}
+}
@@ -16,7 +16,9 @@ type Handlers struct {
 	Ingest *ingest.Service
 	Stats  *Stats
 
-	MQTTStatus func() any
+	MQTTStatus   func() any
+	PollerStatus func() any
+	ModbusStatus func() any
 
 	EnableIngest      bool
 	EnableRead        bool
@@ -0,0 +1,32 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"modbus-memory-appliance/internal/core"
+	"modbus-memory-appliance/internal/ingest"
+)
+
+// newTestHandlers serves memory "test" (10 per area) with ingest enabled.
+func newTestHandlers(mem *core.Memory) *Handlers {
+	memories := map[string]*core.Memory{"test": mem}
+	return &Handlers{
+		Memories:     memories,
+		Ingest:       ingest.New(memories),
+		Stats:        NewStats(),
+		EnableIngest: true,
+	}
+}
+
+// post sends body to handler and returns the recorded response.
+func post(t *testing.T, handler http.HandlerFunc, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
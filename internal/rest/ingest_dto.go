@@ -7,3 +7,31 @@ type ingestRequest struct {
 	Bools   []int    `json:"bools,omitempty"`
 	Values  []uint16 `json:"values,omitempty"`
 }
+
+// ingestTransaction is a batch of ingest writes applied atomically,
+// optionally only if the touched areas are unchanged since
+// if_generation.
+type ingestTransaction struct {
+	Memory       string        `json:"memory"`
+	Writes       []ingestWrite `json:"writes"`
+	IfGeneration string        `json:"if_generation,omitempty"`
+}
+
+// ingestWrite is one write of a transaction, optionally guarded by
+// expected values.
+type ingestWrite struct {
+	ingestRequest
+
+	ExpectBools  []int    `json:"expect_bools,omitempty"`
+	ExpectValues []uint16 `json:"expect_values,omitempty"`
+}
+
+// conditionalRequest is a single ingest write guarded by expected
+// values and / or a generation.
+type conditionalRequest struct {
+	ingestRequest
+
+	ExpectBools  []int    `json:"expect_bools,omitempty"`
+	ExpectValues []uint16 `json:"expect_values,omitempty"`
+	IfGeneration string   `json:"if_generation,omitempty"`
+}
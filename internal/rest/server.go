@@ -34,15 +34,31 @@ func NewServer(
 		mux.Handle("/api/v1/ingest",
 			authMiddleware(http.HandlerFunc(handlers.HandleIngest)),
 		)
+		mux.Handle("/api/v1/ingest/transaction",
+			authMiddleware(http.HandlerFunc(handlers.HandleIngestTransaction)),
+		)
+		mux.Handle("/api/v1/ingest/conditional",
+			authMiddleware(http.HandlerFunc(handlers.HandleIngestConditional)),
+		)
 	} else {
 		// fallback: no auth middleware
 		mux.HandleFunc("/api/v1/ingest",
 			handlers.HandleIngest)
+		mux.HandleFunc("/api/v1/ingest/transaction",
+			handlers.HandleIngestTransaction)
+		mux.HandleFunc("/api/v1/ingest/conditional",
+			handlers.HandleIngestConditional)
 	}
 
 	mux.HandleFunc("/api/v1/diagnostics/mqtt",
 		handlers.HandleDiagnosticsMQTT)
 
+	mux.HandleFunc("/api/v1/diagnostics/pollers",
+		handlers.HandleDiagnosticsPollers)
+
+	mux.HandleFunc("/api/v1/diagnostics/modbus",
+		handlers.HandleDiagnosticsModbus)
+
 	// ---- server ----
 
 	return &http.Server{
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"modbus-memory-appliance/internal/core"
 	"modbus-memory-appliance/internal/ingest"
 )
 
@@ -18,6 +19,18 @@ func writeIngestError(w http.ResponseWriter, err error) {
 		errors.Is(err, ingest.ErrPayloadMismatch):
 		http.Error(w, err.Error(), http.StatusBadRequest)
 
+	// State Sealing: memory is in RUN
+	case errors.Is(err, ingest.ErrIngestDenied):
+		http.Error(w, err.Error(), http.StatusForbidden)
+
+	// Outside the configured address window
+	case errors.Is(err, core.ErrOutOfRange):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+	// Conditional write: the condition no longer holds
+	case errors.Is(err, core.ErrConflict):
+		http.Error(w, err.Error(), http.StatusConflict)
+
 	default:
 		http.Error(w, "internal error", http.StatusInternalServerError)
 	}